@@ -1,7 +1,7 @@
 //
 // go.cli :: action.go
 //
-//   Copyright (c) 2014-2020 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -35,6 +35,7 @@ func Chain(ctx *Context) error {
 		default:
 		}
 
+		ctx.nested = false
 		cmd, err := ctx.Command()
 		if cmd != nil {
 			if err = ctx.Prepare(cmd); err == nil {
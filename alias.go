@@ -0,0 +1,93 @@
+//
+// go.cli :: alias.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+type ExitStatus int
+
+func (e ExitStatus) Error() string { return fmt.Sprintf("exit status %v", int(e)) }
+func (e ExitStatus) ExitCode() int { return int(e) }
+
+func shellAlias(name, s string) *Command {
+	return &Command{
+		Name: []string{name},
+		Desc: "!" + s,
+		Action: func(ctx *Context) error {
+			err := runShell(ctx, name, s, ctx.Args)
+			// the arguments are consumed by the shell
+			ctx.Args = nil
+			return err
+		},
+	}
+}
+
+func runShell(ctx *Context, name, s string, args []string) error {
+	cmd := shellCommand(ctx.Context(), name, s, args)
+	cmd.Env = ctx.UI.Env
+	return runProcess(ctx, cmd)
+}
+
+// runProcess runs cmd with the standard streams of the CLI, and reports its
+// exit status as ExitStatus.
+func runProcess(ctx *Context, cmd *exec.Cmd) error {
+	cmd.Stdin = ctx.UI.Stdin
+	cmd.Stdout = ctx.UI.Stdout
+	cmd.Stderr = ctx.UI.Stderr
+	cmd.Dir = ctx.UI.Dir
+	err := cmd.Run()
+	var ee *exec.ExitError
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Context().Err() != nil:
+		return Interrupt{}
+	case errors.As(err, &ee):
+		if code := ee.ExitCode(); code > 0 {
+			return ExitStatus(code)
+		}
+		return ExitStatus(1)
+	}
+	return err
+}
+
+type alias struct {
+	Name string
+	Cmd  string
+}
+
+func aliases(m map[string]string) []*alias {
+	list := make([]*alias, 0, len(m))
+	for k, v := range m {
+		list = append(list, &alias{
+			Name: k,
+			Cmd:  v,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func formatAlias(a *alias, sep string, th ...*Theme) string {
+	var b strings.Builder
+	if len(th) > 0 && th[0] != nil {
+		b.WriteString(th[0].Command.Render(a.Name))
+	} else {
+		b.WriteString(a.Name)
+	}
+	b.WriteString(sep)
+	b.WriteString(a.Cmd)
+	return b.String()
+}
@@ -0,0 +1,218 @@
+//
+// go.cli :: alias_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	"github.com/hattya/go.cli"
+)
+
+func TestAlias(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Stdout = &stdout
+	app.Stderr = &stderr
+	app.Flags.Bool("v", false, "")
+	flags := cli.NewFlagSet()
+	flags.Int("n", 0, "")
+	app.Add(&cli.Command{
+		Name:  []string{"echo"},
+		Flags: flags,
+		Action: func(ctx *cli.Context) error {
+			ctx.UI.Printf("%v %v %q\n", ctx.Bool("v"), ctx.Int("n"), ctx.Args)
+			return nil
+		},
+	})
+	app.Aliases = map[string]string{
+		"e":     "echo -n 1",
+		"ee":    "e foo",
+		"echo":  "fail",
+		"loop":  "loop1",
+		"loop1": "loop",
+		"quote": "echo 'foo",
+		"empty": " ",
+	}
+	for _, tt := range []struct {
+		args []string
+		out  string
+	}{
+		{[]string{"e"}, `false 1 []`},
+		{[]string{"e", "-n", "2", "bar"}, `false 2 ["bar"]`},
+		{[]string{"ee", "bar"}, `false 1 ["foo" "bar"]`},
+		{[]string{"-v", "ee"}, `true 1 ["foo"]`},
+		{[]string{"echo"}, `false 0 []`},
+		{[]string{"ec"}, `false 0 []`},
+	} {
+		stdout.Reset()
+		if err := app.Run(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		if err := testOut(stdout.String(), tt.out+"\n"); err != nil {
+			t.Error(err)
+		}
+	}
+
+	for _, tt := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"loop"}, "alias 'loop' is recursive"},
+		{[]string{"quote"}, "alias 'quote': cli: unterminated quoted string"},
+		{[]string{"empty"}, "alias 'empty' is empty"},
+	} {
+		stderr.Reset()
+		switch err := app.Run(tt.args); {
+		case err == nil:
+			t.Errorf("%v: expected error", tt.args)
+		case err.Error() != tt.err:
+			t.Errorf("%v: unexpected error: %v", tt.args, err)
+		}
+		if g, e := stderr.String(), "app: "+tt.err+"\n"; g != e {
+			t.Errorf("expected %q, got %q", e, g)
+		}
+	}
+}
+
+func TestAliasChain(t *testing.T) {
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Action = cli.Chain
+	app.Stdout = &b
+	app.Stderr = &b
+	for _, n := range []string{"foo", "bar"} {
+		app.Add(&cli.Command{
+			Name:  []string{n},
+			Flags: cli.NewFlagSet(),
+			Action: func(ctx *cli.Context) error {
+				ctx.UI.Println(n)
+				return nil
+			},
+		})
+	}
+	app.Add(&cli.Command{
+		Name:  []string{"cmd"},
+		Flags: cli.NewFlagSet(),
+		Cmds: []*cli.Command{
+			cli.NewHelpCommand(),
+		},
+		Action: cli.Subcommand,
+	})
+	app.Aliases = map[string]string{
+		"b": "bar",
+	}
+	if err := app.Run([]string{"foo", "b", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(b.String(), "foo\nbar\nbar\n"); err != nil {
+		t.Error(err)
+	}
+
+	// aliases are not subcommands
+	if err := app.Run([]string{"cmd", "help", "b"}); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestShellAlias(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires /bin/sh")
+	}
+
+	var stdout, stderr bytes.Buffer
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Stdout = &stdout
+	app.Stderr = &stderr
+	app.Add(&cli.Command{
+		Name:  []string{"echo"},
+		Flags: cli.NewFlagSet(),
+		Action: func(ctx *cli.Context) error {
+			ctx.UI.Println("echo")
+			return nil
+		},
+	})
+	app.Aliases = map[string]string{
+		"sh":    "!echo $0 \"$#\"",
+		"false": "!exit 3",
+	}
+	if err := app.Run([]string{"sh", "foo", "bar baz"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(stdout.String(), "sh 2 foo bar baz\n"); err != nil {
+		t.Error(err)
+	}
+
+	// the arguments are not run by Chain
+	stdout.Reset()
+	app.Action = cli.Chain
+	if err := app.Run([]string{"sh", "echo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(stdout.String(), "sh 1 echo\n"); err != nil {
+		t.Error(err)
+	}
+
+	err := app.Run([]string{"false"})
+	if g, e := err, cli.ExitStatus(3); g != e {
+		t.Errorf("expected %#v, got %#v", e, g)
+	}
+	if g, e := cli.ExitCode(err), 3; g != e {
+		t.Errorf("ExitCode() = %v, expected %v", g, e)
+	}
+	if g := stderr.String(); g != "" {
+		t.Errorf("unexpected output: %q", g)
+	}
+}
+
+func TestAliasHelp(t *testing.T) {
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Flags.Bool("v", false, "")
+	app.Add(&cli.Command{
+		Name:  []string{"echo"},
+		Flags: cli.NewFlagSet(),
+	})
+	app.Aliases = map[string]string{
+		"e":  "echo -n 1",
+		"sh": "!echo",
+	}
+	if err := app.Run([]string{"-h"}); err != nil {
+		t.Fatal(err)
+	}
+	out := cli.Dedent(`
+		usage: app
+
+		commands:
+
+		  echo
+
+		aliases:
+
+		  e     echo -n 1
+		  sh    !echo
+
+		options:
+
+		  -h, --help    show help
+		  -v
+		  --version    show version information
+
+	`)
+	if err := testOut(b.String(), out); err != nil {
+		t.Error(err)
+	}
+}
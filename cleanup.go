@@ -0,0 +1,73 @@
+//
+// go.cli :: cleanup.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrGracePeriod = errors.New("cli: grace period expired")
+
+func (ctx *Context) Defer(fn func() error) {
+	ctx.cleanups = append(ctx.cleanups, fn)
+}
+
+func (ctx *Context) cleanup() error {
+	if len(ctx.cleanups) == 0 {
+		return nil
+	}
+	list := ctx.cleanups
+	ctx.cleanups = nil
+
+	// once the grace period has expired, cleanups still running are left
+	// behind and must not report anything
+	var mu sync.Mutex
+	expired := false
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		for i := len(list) - 1; i >= 0; i-- {
+			if e := list[i](); e != nil {
+				mu.Lock()
+				if !expired {
+					ctx.ErrorHandler(e)
+				}
+				mu.Unlock()
+				if err == nil {
+					err = e
+				}
+			}
+		}
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Context().Done():
+	}
+	// without a grace period, wait for the cleanups to finish however long
+	// they take
+	if ctx.UI.GracePeriod <= 0 {
+		return <-done
+	}
+
+	t := time.NewTimer(ctx.UI.GracePeriod)
+	defer t.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-t.C:
+	}
+	mu.Lock()
+	expired = true
+	mu.Unlock()
+	return ctx.ErrorHandler(ErrGracePeriod)
+}
@@ -0,0 +1,157 @@
+//
+// go.cli :: cleanup_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hattya/go.cli"
+)
+
+func TestDefer(t *testing.T) {
+	for _, e := range []error{nil, errors.New("error"), cli.Interrupt{}} {
+		var list []int
+		app := cli.NewCLI()
+		app.Stdout = io.Discard
+		app.Stderr = io.Discard
+		app.Action = func(ctx *cli.Context) error {
+			for i := range 3 {
+				ctx.Defer(func() error {
+					list = append(list, i)
+					return nil
+				})
+			}
+			if _, ok := e.(cli.Interrupt); ok {
+				ctx.Interrupt()
+				return nil
+			}
+			return e
+		}
+		if err := app.Run(nil); err != e {
+			t.Errorf("expected %#v, got %#v", e, err)
+		}
+		if g, e := list, []int{2, 1, 0}; !reflect.DeepEqual(g, e) {
+			t.Errorf("expected %v, got %v", e, g)
+		}
+	}
+}
+
+func TestDeferError(t *testing.T) {
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Action = func(ctx *cli.Context) error {
+		for i := range 2 {
+			ctx.Defer(func() error {
+				return fmt.Errorf("cleanup %v", i)
+			})
+		}
+		return nil
+	}
+	switch err := app.Run(nil); {
+	case err == nil:
+		t.Error("expected error")
+	case err.Error() != "cleanup 1":
+		t.Error("unexpected error:", err)
+	}
+	out := cli.Dedent(`
+		%[1]v: cleanup 1
+		%[1]v: cleanup 0
+	`)
+	if err := testOut(b.String(), fmt.Sprintf(out, app.Name)); err != nil {
+		t.Error(err)
+	}
+
+	// action error takes precedence
+	b.Reset()
+	app = cli.NewCLI()
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Action = func(ctx *cli.Context) error {
+		ctx.Defer(func() error {
+			return fmt.Errorf("cleanup")
+		})
+		return fmt.Errorf("action")
+	}
+	switch err := app.Run(nil); {
+	case err == nil:
+		t.Error("expected error")
+	case err.Error() != "action":
+		t.Error("unexpected error:", err)
+	}
+	out = cli.Dedent(`
+		%[1]v: action
+		%[1]v: cleanup
+	`)
+	if err := testOut(b.String(), fmt.Sprintf(out, app.Name)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGracePeriod(t *testing.T) {
+	for _, d := range []time.Duration{0, 1 * time.Second} {
+		app := cli.NewCLI()
+		app.GracePeriod = d
+		app.Stdout = io.Discard
+		app.Stderr = io.Discard
+		app.Action = func(ctx *cli.Context) error {
+			ctx.Defer(func() error {
+				time.Sleep(10 * time.Millisecond)
+				return nil
+			})
+			ctx.Interrupt()
+			return nil
+		}
+		switch err := app.Run(nil).(type) {
+		case cli.Interrupt:
+		default:
+			t.Errorf("expected Interrupt, got %#v", err)
+		}
+	}
+
+	var b bytes.Buffer
+	block := make(chan struct{})
+	done := make(chan struct{})
+	app := cli.NewCLI()
+	app.GracePeriod = 10 * time.Millisecond
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Action = func(ctx *cli.Context) error {
+		ctx.Defer(func() error {
+			close(done)
+			return fmt.Errorf("too late")
+		})
+		ctx.Defer(func() error {
+			<-block
+			return fmt.Errorf("too late")
+		})
+		ctx.Interrupt()
+		return nil
+	}
+	if err := app.Run(nil); err != cli.ErrGracePeriod {
+		t.Errorf("expected ErrGracePeriod, got %#v", err)
+	}
+	out := cli.Dedent(`
+		%[1]v: interrupted
+		%[1]v: cli: grace period expired
+	`)
+	// cleanups finished after the grace period do not report errors
+	close(block)
+	<-done
+	if err := testOut(b.String(), fmt.Sprintf(out, app.Name)); err != nil {
+		t.Error(err)
+	}
+}
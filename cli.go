@@ -1,7 +1,7 @@
 //
 // go.cli :: cli.go
 //
-//   Copyright (c) 2014-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -10,6 +10,7 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -17,18 +18,23 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/term"
 )
 
 type CLI struct {
-	Name    string
-	Version string
-	Usage   any
-	Desc    string
-	Epilog  string
-	Cmds    []*Command
-	Flags   *FlagSet
+	Name     string
+	Version  string
+	Usage    any
+	Desc     string
+	Epilog   string
+	Examples []Example
+	Cmds     []*Command
+	Topics   []*Topic
+	Aliases  map[string]string
+	Flags    *FlagSet
 
 	Prepare      func(*Context, *Command) error
 	Action       Action
@@ -37,7 +43,27 @@ type CLI struct {
 	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
+	Exit   func(int)
+	Env    []string
+	Dir    string
 
+	Color    ColorMode
+	Theme    *Theme
+	Pager    string
+	PageHelp bool
+
+	HandleSignals  bool
+	GracePeriod    time.Duration
+	Recover        bool
+	Plugins        bool
+	PluginDirs     []string
+	ResponseFiles  bool
+	AssumeYes      bool
+	NonInteractive InputPolicy
+	ScriptErrors   ErrorPolicy
+
+	in      *input
+	run     *run
 	ctx     context.Context
 	cancel  context.CancelFunc
 	help    bool
@@ -53,12 +79,31 @@ func NewCLI() *CLI {
 	return &CLI{
 		Name:   name,
 		Flags:  NewFlagSet(),
+		in:     new(input),
 		ctx:    ctx,
 		cancel: cancel,
 	}
 }
 
 func (ui *CLI) Run(args []string) error {
+	ui = ui.newRun()
+	defer ui.cancel()
+	return ui.runArgs(args)
+}
+
+// newRun returns a copy of the CLI which has its own flags and cancellation
+// for a run.
+func (ui *CLI) newRun() *CLI {
+	origin := ui
+	r := *ui
+	ui = &r
+	ui.run = &run{origin: origin}
+	parent := ui.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ui.ctx, ui.cancel = context.WithCancel(parent)
+
 	if ui.Action == nil {
 		ui.Action = DefaultAction
 	}
@@ -76,6 +121,12 @@ func (ui *CLI) Run(args []string) error {
 		ui.Stderr = os.Stderr
 	}
 
+	if ui.Flags == nil {
+		ui.Flags = NewFlagSet()
+	} else {
+		ui.Flags = ui.Flags.Clone()
+		ui.Flags.setEnv(ui.Getenv)
+	}
 	if ui.Flags.Lookup("h") == nil && ui.Flags.Lookup("help") == nil {
 		ui.Flags.Bool("h, help", false, "show help")
 		ui.help = true
@@ -84,6 +135,13 @@ func (ui *CLI) Run(args []string) error {
 		ui.Flags.Bool("version", false, "show version information")
 		ui.version = true
 	}
+	return ui
+}
+
+func (ui *CLI) runArgs(args []string) error {
+	if ui.HandleSignals {
+		defer ui.notify()()
+	}
 
 	ctx := NewContext(ui)
 	select {
@@ -91,23 +149,85 @@ func (ui *CLI) Run(args []string) error {
 		return ctx.ErrorHandler(Interrupt{})
 	default:
 	}
+	if ui.ResponseFiles {
+		var err error
+		if args, err = ui.expandArgs(args); err != nil {
+			return ctx.ErrorHandler(err)
+		}
+	}
 	if err := ui.Flags.Parse(args); err != nil {
 		return ctx.ErrorHandler(err)
 	}
 	ctx.Args = ui.Flags.Args()
 	switch {
 	case ui.help && ctx.Bool("help"):
-		return Help(ctx)
+		return showHelp(ctx)
 	case ui.version && ctx.Bool("version"):
 		return Version(ctx)
 	}
-	err := ui.Action(ctx)
+	if f := ui.Flags.lookupRole(roleScript); f != nil {
+		if name := f.Value.String(); name != "" {
+			return ui.runScript(ctx, name)
+		}
+	}
+	err := ui.action(ctx, args)
 	select {
 	case <-ui.ctx.Done():
-		err = Interrupt{}
+		if !errors.Is(err, Interrupt{}) {
+			err = Interrupt{}
+		}
 	default:
 	}
-	return ctx.ErrorHandler(err)
+	err = ctx.ErrorHandler(err)
+	if cerr := ctx.cleanup(); err == nil || errors.Is(cerr, ErrGracePeriod) {
+		err = cerr
+	}
+	return err
+}
+
+// run is the state of a run.
+type run struct {
+	origin *CLI // the CLI which the run is made from
+	mu     sync.Mutex
+	flags  map[*FlagSet]*FlagSet
+}
+
+// flagSet returns the clone of fs for the run.
+func (ui *CLI) flagSet(fs *FlagSet) *FlagSet {
+	if ui.run == nil {
+		return fs
+	}
+	ui.run.mu.Lock()
+	defer ui.run.mu.Unlock()
+	c, ok := ui.run.flags[fs]
+	if !ok {
+		if ui.run.flags == nil {
+			ui.run.flags = make(map[*FlagSet]*FlagSet)
+		}
+		c = fs.Clone()
+		c.setEnv(ui.Getenv)
+		ui.run.flags[fs] = c
+	}
+	return c
+}
+
+// resetFlags discards the parsed state of the flags for the run.
+func (ui *CLI) resetFlags() {
+	ui.Flags.Reset()
+	ui.Flags.setEnv(ui.Getenv)
+	if ui.run != nil {
+		ui.run.mu.Lock()
+		ui.run.flags = nil
+		ui.run.mu.Unlock()
+	}
+}
+
+func (ui *CLI) Main(args []string) {
+	exit := ui.Exit
+	if exit == nil {
+		exit = os.Exit
+	}
+	exit(ExitCode(ui.Run(args)))
 }
 
 func (ui *CLI) Add(cmd *Command) {
@@ -122,6 +242,25 @@ func (ui *CLI) Interrupt() {
 	ui.cancel()
 }
 
+func (ui *CLI) Getenv(key string) string {
+	if ui.Env == nil {
+		return os.Getenv(key)
+	}
+	for i := len(ui.Env) - 1; i >= 0; i-- {
+		if k, v, ok := strings.Cut(ui.Env[i], "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+func (ui *CLI) Getwd() (string, error) {
+	if ui.Dir != "" {
+		return ui.Dir, nil
+	}
+	return os.Getwd()
+}
+
 func (ui *CLI) Print(a ...any) (int, error) {
 	return fmt.Fprint(ui.Stdout, a...)
 }
@@ -150,42 +289,37 @@ func (ui *CLI) Title(title string) error {
 	return ui.title(title)
 }
 
-func (ui *CLI) Prompt(prompt string) (string, error) {
-	ui.Print(prompt)
-	return ui.readLine()
+func (ui *CLI) Prompt(prompt string, opts ...PromptOption) (string, error) {
+	return ui.PromptContext(ui.Context(), prompt, opts...)
 }
 
-func (ui *CLI) Password(prompt string) (string, error) {
-	ui.Print(prompt)
-	defer ui.Println()
-	if f, ok := ui.Stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
-		b, err := term.ReadPassword(int(f.Fd()))
-		return string(b), err
+func (ui *CLI) PromptContext(ctx context.Context, prompt string, opts ...PromptOption) (string, error) {
+	o := newPromptOptions(opts)
+	if o.hasDef {
+		prompt = defaultPrompt(prompt, o.def)
 	}
-	return ui.readLine()
+	return o.input(ui, prompt, func(prompt string) (string, error) {
+		if ui.interactive() {
+			return ui.editLine(ctx, prompt, o)
+		}
+		ui.Print(prompt)
+		return ui.readLine(ctx)
+	})
 }
 
-func (ui *CLI) readLine() (string, error) {
-	b := make([]byte, 1)
-	var in []byte
-	for {
-		n, err := ui.Stdin.Read(b)
-		if n > 0 {
-			if b[0] == '\n' {
-				if len(in) > 0 && in[len(in)-1] == '\r' {
-					in = in[:len(in)-1]
-				}
-				return string(in), nil
-			}
-			in = append(in, b...)
-		}
-		if err != nil {
-			if err == io.EOF && len(in) > 0 {
-				err = nil
-			}
-			return string(in), err
+func (ui *CLI) Password(prompt string, opts ...PromptOption) (string, error) {
+	return ui.PasswordContext(ui.Context(), prompt, opts...)
+}
+
+func (ui *CLI) PasswordContext(ctx context.Context, prompt string, opts ...PromptOption) (string, error) {
+	return newPromptOptions(opts).input(ui, prompt, func(prompt string) (string, error) {
+		ui.Print(prompt)
+		defer ui.Println()
+		if f, ok := ui.Stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			return ui.readPassword(ctx, int(f.Fd()))
 		}
-	}
+		return ui.readLine(ctx)
+	})
 }
 
 var (
@@ -194,42 +328,187 @@ var (
 	ErrArgs    = errors.New("invalid arguments")
 )
 
+type Errors []error
+
+func (e Errors) Error() string {
+	list := make([]string, len(e))
+	for i, err := range e {
+		list[i] = err.Error()
+	}
+	return strings.Join(list, "\n")
+}
+
+func (e Errors) Unwrap() []error { return e }
+
+func (e Errors) Err() error {
+	switch len(e) {
+	case 0:
+		return nil
+	case 1:
+		return e[0]
+	}
+	return e
+}
+
+type ExitCoder interface {
+	ExitCode() int
+}
+
+func ExitCode(err error) int {
+	var ec ExitCoder
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &ec):
+		return ec.ExitCode()
+	case errors.Is(err, ErrCommand), errors.Is(err, ErrArgs):
+		return 2
+	}
+	return 1
+}
+
 type Abort struct {
 	Err  error
 	Hint string
 }
 
 func (e Abort) Error() string { return e.Err.Error() }
+func (e Abort) Unwrap() error { return e.Err }
+func (e Abort) ExitCode() int { return 1 }
 
 type Interrupt struct{}
 
 func (e Interrupt) Error() string { return "interrupted" }
+func (e Interrupt) ExitCode() int { return 130 }
+
+var ErrorFormats = map[string]any{
+	"text": "text",
+	"json": "json",
+}
+
+func (fs *FlagSet) ErrorFormatFlag(name, usage string) *Flag {
+	if name == "" {
+		name = "error-format"
+	}
+	if usage == "" {
+		usage = "format of error messages: text or json"
+	}
+	f := fs.Choice(name, "text", ErrorFormats, usage)
+	f.MetaVar = " <format>"
+	f.role = roleErrorFormat
+	return f
+}
 
 func ErrorHandler(ctx *Context, err error) error {
-	switch err := err.(type) {
-	case nil:
-	case Abort:
-		ctx.UI.Errorf("%v: %v\n", ctx.UI.Name, err)
-		if err.Hint != "" {
-			ctx.UI.Errorln(err.Hint)
+	if err == nil {
+		return nil
+	}
+	if ctx.Flags != nil {
+		if f := ctx.Flags.lookupRole(roleErrorFormat); f != nil {
+			if v, ok := f.Value.Get().(string); ok && v == "json" {
+				return JSONErrorHandler(ctx, err)
+			}
 		}
-	case CommandError:
-		if len(err.List) == 0 {
-			ctx.UI.Errorf("%v: %v\n", ctx.Name(), err)
-			Help(ctx)
-		} else {
-			ctx.UI.Errorf("%v: command '%v' is ambiguous\n", ctx.Name(), err.Name)
-			ctx.UI.Errorf("    %v\n", strings.Join(err.List, " "))
+	}
+	var help bool
+	if errs, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range errs.Unwrap() {
+			if reportError(ctx, e) {
+				help = true
+			}
 		}
-	case FlagError:
-		ctx.UI.Errorf("%v: %v\n", ctx.Name(), err)
+	} else {
+		help = reportError(ctx, err)
+	}
+	if help {
 		Help(ctx)
-	default:
-		if err == ErrCommand {
-			Help(ctx)
-		} else {
-			ctx.UI.Errorf("%v: %v\n", ctx.Name(), err)
+	}
+	return err
+}
+
+func JSONErrorHandler(ctx *Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	list := []error{err}
+	if errs, ok := err.(interface{ Unwrap() []error }); ok {
+		list = errs.Unwrap()
+	}
+	enc := json.NewEncoder(ctx.UI.Stderr)
+	for _, e := range list {
+		v := jsonError{
+			Kind:    "error",
+			Message: e.Error(),
+			Command: ctx.Name(),
+		}
+		var abort Abort
+		var cmdErr CommandError
+		switch {
+		case errors.Is(e, Interrupt{}):
+			v.Kind = "interrupt"
+		case errors.As(e, new(InternalError)):
+			v.Kind = "internal"
+		case errors.As(e, &abort):
+			v.Kind = "abort"
+			v.Hint = abort.Hint
+		case errors.As(e, &cmdErr):
+			v.Kind = "command"
+			v.Candidates = cmdErr.List
+		case errors.Is(e, ErrCommand):
+			v.Kind = "command"
+		case errors.As(e, new(FlagError)):
+			v.Kind = "flag"
+		case errors.Is(e, ErrArgs):
+			v.Kind = "args"
 		}
+		enc.Encode(v)
 	}
 	return err
 }
+
+type jsonError struct {
+	Kind       string   `json:"kind"`
+	Message    string   `json:"message"`
+	Hint       string   `json:"hint,omitempty"`
+	Candidates []string `json:"candidates,omitempty"`
+	Command    string   `json:"command"`
+}
+
+func reportError(ctx *Context, err error) (help bool) {
+	th := ctx.Theme(ctx.UI.Stderr)
+	var abort Abort
+	var cmdErr CommandError
+	var flagErr FlagError
+	var ie InternalError
+	switch {
+	case err == nil:
+	case errors.As(err, new(ExitStatus)):
+		// already reported by the process
+	case errors.As(err, &ie):
+		ctx.UI.Errorf("%v %v\n", th.Error.Render(ctx.UI.Name+":"), err)
+		if ie.Report != "" {
+			ctx.UI.Errorf("crash report written to %v\n", ie.Report)
+		}
+	case errors.As(err, &abort):
+		ctx.UI.Errorf("%v %v\n", th.Error.Render(ctx.UI.Name+":"), err)
+		if abort.Hint != "" {
+			ctx.UI.Errorln(abort.Hint)
+		}
+	case errors.As(err, &cmdErr):
+		if len(cmdErr.List) == 0 {
+			ctx.UI.Errorf("%v %v\n", th.Error.Render(ctx.Name()+":"), err)
+			help = true
+		} else {
+			ctx.UI.Errorf("%v command '%v' is ambiguous\n", th.Error.Render(ctx.Name()+":"), cmdErr.Name)
+			ctx.UI.Errorf("    %v\n", strings.Join(cmdErr.List, " "))
+		}
+	case errors.As(err, &flagErr):
+		ctx.UI.Errorf("%v %v\n", th.Error.Render(ctx.Name()+":"), err)
+		help = true
+	case errors.Is(err, ErrCommand):
+		help = true
+	default:
+		ctx.UI.Errorf("%v %v\n", th.Error.Render(ctx.Name()+":"), err)
+	}
+	return
+}
@@ -1,7 +1,7 @@
 //
 // go.cli :: cli_test.go
 //
-//   Copyright (c) 2014-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -10,12 +10,14 @@ package cli_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -45,10 +47,14 @@ func TestCLI(t *testing.T) {
 	app.Flags.Uint("uint", 0, "")
 	app.Flags.Uint64("uint64", 0, "")
 	app.Flags.Var("var", new(value), "")
+	var ctx *cli.Context
+	app.Action = func(c *cli.Context) error {
+		ctx = c
+		return nil
+	}
 	if err := app.Run(strings.Fields("-bool -duration 1ms -float64 3.14 -int -1 -int64 -64 -string string -uint 1 -uint64 64 -var var 0 1")); err != nil {
 		t.Fatal(err)
 	}
-	ctx := cli.NewContext(app)
 	if g, e := len(ctx.Args), 2; g != e {
 		t.Errorf("len(Context.Args) = %v, expected %v", g, e)
 	}
@@ -84,6 +90,156 @@ func TestCLI(t *testing.T) {
 			t.Errorf("Context.%v(%q) = %v, expected %v", m, tt.name, g, e)
 		}
 	}
+	// Run parses copies of the flags, and the flags of the CLI keep their
+	// defaults
+	if cli.NewContext(app).Bool("bool") {
+		t.Error("flags of the CLI are modified by Run")
+	}
+	app.Flags.VisitAll(func(f *cli.Flag) {
+		if g, e := f.Value.String(), f.Default; g != e {
+			t.Errorf("Flag.Value of -%v = %q, expected %q", f.Name[0], g, e)
+		}
+	})
+}
+
+func TestRunConcurrent(t *testing.T) {
+	app := cli.NewCLI()
+	app.Stdout = io.Discard
+	app.Stderr = io.Discard
+	app.Flags.Int("i", 0, "")
+	flags := cli.NewFlagSet()
+	flags.String("s", "", "")
+	app.Add(&cli.Command{
+		Name:  []string{"cmd"},
+		Flags: flags,
+		Action: func(ctx *cli.Context) error {
+			if g, e := ctx.String("s"), strconv.Itoa(ctx.Int("i")); g != e {
+				return fmt.Errorf("expected %q, got %q", e, g)
+			}
+			if len(ctx.Args) > 0 {
+				ctx.Interrupt()
+			}
+			return nil
+		},
+	})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 16)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			args := []string{"-i", strconv.Itoa(i), "cmd", "-s", strconv.Itoa(i)}
+			if i%2 == 1 {
+				args = append(args, "interrupt")
+			}
+			errs[i] = app.Run(args)
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		switch {
+		case i%2 == 0 && err != nil:
+			t.Errorf("Run #%v: unexpected error: %v", i, err)
+		case i%2 == 1 && err != (cli.Interrupt{}):
+			t.Errorf("Run #%v: expected Interrupt, got %#v", i, err)
+		}
+	}
+
+	// flags are not modified
+	if app.Flags.Lookup("help") != nil || app.Flags.Lookup("version") != nil {
+		t.Error("flags are added by Run")
+	}
+	if g, e := app.Flags.Get("i"), 0; g != e {
+		t.Errorf("FlagSet.Get(%q) = %v, expected %v", "i", g, e)
+	}
+	if g, e := flags.Get("s"), ""; g != e {
+		t.Errorf("FlagSet.Get(%q) = %v, expected %v", "s", g, e)
+	}
+	if err := app.Run([]string{"cmd", "-s", "0"}); err != nil {
+		t.Error(err)
+	}
+
+	app.Interrupt()
+	if err := app.Run([]string{"cmd"}); err != (cli.Interrupt{}) {
+		t.Errorf("expected Interrupt, got %#v", err)
+	}
+}
+
+func TestGetenv(t *testing.T) {
+	t.Setenv("__CLI_FOO__", "foo")
+
+	app := cli.NewCLI()
+	if g, e := app.Getenv("__CLI_FOO__"), "foo"; g != e {
+		t.Errorf("Getenv() = %q, expected %q", g, e)
+	}
+	app.Env = []string{"__CLI_FOO__=bar", "__CLI_FOO__=baz"}
+	if g, e := app.Getenv("__CLI_FOO__"), "baz"; g != e {
+		t.Errorf("Getenv() = %q, expected %q", g, e)
+	}
+	app.Env = []string{}
+	if g, e := app.Getenv("__CLI_FOO__"), ""; g != e {
+		t.Errorf("Getenv() = %q, expected %q", g, e)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, err := app.Getwd(); err != nil || g != wd {
+		t.Errorf("Getwd() = %q, %v, expected %q, nil", g, err, wd)
+	}
+	app.Dir = "/dir"
+	if g, err := app.Getwd(); err != nil || g != "/dir" {
+		t.Errorf("Getwd() = %q, %v, expected %q, nil", g, err, "/dir")
+	}
+}
+
+func TestEnvFlags(t *testing.T) {
+	t.Setenv("__CLI_FOO__", "")
+	t.Setenv("__CLI_BAR__", "")
+
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Flags.StringEnv("__CLI_FOO__", "foo", "default", "")
+	flags := cli.NewFlagSet()
+	flags.StringEnv("__CLI_BAR__", "bar", "default", "")
+	app.Add(&cli.Command{
+		Name:  []string{"cmd"},
+		Flags: flags,
+		Action: func(ctx *cli.Context) error {
+			ctx.UI.Println(ctx.String("foo"), ctx.String("bar"))
+			return nil
+		},
+	})
+	for _, tt := range []struct {
+		env  []string
+		args []string
+		out  string
+	}{
+		{nil, []string{"cmd"}, "os os"},
+		{nil, []string{"--foo", "arg", "cmd"}, "arg os"},
+		{[]string{"__CLI_FOO__=env"}, []string{"cmd"}, "env default"},
+		{[]string{"__CLI_FOO__=env"}, []string{"--foo", "arg", "cmd"}, "arg default"},
+		{[]string{"__CLI_BAR__=env"}, []string{"cmd", "--bar", "arg"}, "default arg"},
+	} {
+		if tt.env == nil {
+			// the environment variables are read at run time
+			t.Setenv("__CLI_FOO__", "os")
+			t.Setenv("__CLI_BAR__", "os")
+		}
+		b.Reset()
+		app.Env = tt.env
+		if err := app.Run(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		if err := testOut(b.String(), tt.out+"\n"); err != nil {
+			t.Errorf("%v %v: %v", tt.env, tt.args, err)
+		}
+	}
 }
 
 func TestCLIOut(t *testing.T) {
@@ -120,6 +276,64 @@ func TestInterrupt(t *testing.T) {
 	}
 }
 
+func TestInterruptWrapped(t *testing.T) {
+	app := cli.NewCLI()
+	app.Stdout = io.Discard
+	app.Stderr = io.Discard
+	app.Action = func(ctx *cli.Context) error {
+		ctx.Interrupt()
+		return fmt.Errorf("wrapped: %w", cli.Interrupt{})
+	}
+	switch err := app.Run(nil); {
+	case !errors.Is(err, cli.Interrupt{}):
+		t.Errorf("expected Interrupt, got %#v", err)
+	case err.Error() != "wrapped: interrupted":
+		t.Error("unexpected error:", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var errs cli.Errors
+	if err := errs.Err(); err != nil {
+		t.Errorf("expected nil, got %#v", err)
+	}
+	errs = append(errs, cli.ErrArgs)
+	if err := errs.Err(); err != cli.ErrArgs {
+		t.Errorf("expected ErrArgs, got %#v", err)
+	}
+	errs = append(errs, cli.FlagError("flag error"))
+	switch err := errs.Err(); {
+	case err.Error() != "invalid arguments\nflag error":
+		t.Error("unexpected error:", err)
+	case !errors.Is(err, cli.ErrArgs):
+		t.Error("Errors does not unwrap")
+	}
+
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Flags.Int("i", 0, "")
+	if err := app.Run([]string{"-x", "-i", "_"}); cli.ExitCode(err) != 2 {
+		t.Errorf("unexpected error: %#v", err)
+	}
+	out := cli.Dedent(`
+		%[1]v: flag provided but not defined: -x
+		%[1]v: invalid value "_" for flag -i: parse error
+		usage: %[1]v
+	`)
+	if g, e := b.String(), fmt.Sprintf(out, app.Name); !strings.HasPrefix(g, e) {
+		t.Errorf("output differ\nexpected: %q\n     got: %q", e, g)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	err := fmt.Errorf("abort")
+	if !errors.Is(cli.Abort{Err: err}, err) {
+		t.Error("Abort does not unwrap")
+	}
+}
+
 func TestTitle(t *testing.T) {
 	app := cli.NewCLI()
 
@@ -295,6 +509,48 @@ var errorHandlerTests = []struct {
 			%v: error
 		`),
 	},
+	{
+		err: fmt.Errorf("wrapped: %w", cli.Abort{
+			Err:  fmt.Errorf("abort"),
+			Hint: "hint",
+		}),
+		out: cli.Dedent(`
+			%v: wrapped: abort
+			hint
+		`),
+	},
+	{
+		err: fmt.Errorf("wrapped: %w", cli.CommandError{
+			Name: "b",
+			List: []string{"bar", "baz"},
+		}),
+		out: cli.Dedent(`
+			%v: command 'b' is ambiguous
+			    bar baz
+		`),
+	},
+	{
+		err: fmt.Errorf("wrapped: %w", cli.FlagError("flag error")),
+		out: cli.Dedent(`
+			%v: wrapped: flag error
+			usage: %[1]v
+		`),
+	},
+	{
+		err: fmt.Errorf("wrapped: %w", cli.ErrCommand),
+		out: cli.Dedent(`
+			usage: %v
+		`),
+	},
+	{
+		err: errors.Join(cli.FlagError("flag error 1"), fmt.Errorf("error"), cli.FlagError("flag error 2")),
+		out: cli.Dedent(`
+			%v: flag error 1
+			%[1]v: error
+			%[1]v: flag error 2
+			usage: %[1]v
+		`),
+	},
 }
 
 func TestErrorHandler(t *testing.T) {
@@ -317,6 +573,149 @@ func TestErrorHandler(t *testing.T) {
 	}
 }
 
+var jsonErrorHandlerTests = []struct {
+	err error
+	out string
+}{
+	{
+		err: nil,
+		out: "",
+	},
+	{
+		err: cli.Abort{
+			Err:  fmt.Errorf("abort"),
+			Hint: "hint",
+		},
+		out: `{"kind":"abort","message":"abort","hint":"hint","command":"%v"}`,
+	},
+	{
+		err: cli.CommandError{
+			Name: "b",
+			List: []string{"bar", "baz"},
+		},
+		out: `{"kind":"command","message":"command 'b' is ambiguous (bar, baz)","candidates":["bar","baz"],"command":"%v"}`,
+	},
+	{
+		err: cli.ErrCommand,
+		out: `{"kind":"command","message":"cli: command required","command":"%v"}`,
+	},
+	{
+		err: fmt.Errorf("wrapped: %w", cli.FlagError("flag error")),
+		out: `{"kind":"flag","message":"wrapped: flag error","command":"%v"}`,
+	},
+	{
+		err: cli.ErrArgs,
+		out: `{"kind":"args","message":"invalid arguments","command":"%v"}`,
+	},
+	{
+		err: cli.Interrupt{},
+		out: `{"kind":"interrupt","message":"interrupted","command":"%v"}`,
+	},
+	{
+		err: errors.Join(fmt.Errorf("error"), cli.FlagError("flag error")),
+		out: `{"kind":"error","message":"error","command":"%v"}` + "\n" + `{"kind":"flag","message":"flag error","command":"%[1]v"}`,
+	},
+}
+
+func TestJSONErrorHandler(t *testing.T) {
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdout = &b
+	app.Stderr = &b
+	ctx := cli.NewContext(app)
+	for _, tt := range jsonErrorHandlerTests {
+		b.Reset()
+
+		cli.JSONErrorHandler(ctx, tt.err)
+		var out string
+		if tt.out != "" {
+			out = fmt.Sprintf(tt.out, ctx.Name()) + "\n"
+		}
+		if err := testOut(b.String(), out); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestErrorFormat(t *testing.T) {
+	for _, env := range []string{"", "json"} {
+		t.Setenv("APP_ERROR_FORMAT", env)
+
+		var b bytes.Buffer
+		app := cli.NewCLI()
+		app.Stdout = &b
+		app.Stderr = &b
+		f := app.Flags.ErrorFormatFlag("", "")
+		f.EnvVar = "APP_ERROR_FORMAT"
+		args := []string{"-_"}
+		if env == "" {
+			args = append([]string{"--error-format", "json"}, args...)
+		}
+		app.Run(args)
+		if err := testOut(b.String(), fmt.Sprintf(`{"kind":"flag","message":"flag provided but not defined: -_","command":"%v"}`+"\n", app.Name)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// a flag which is not registered by ErrorFormatFlag
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Flags.Choice("error-format", "text", cli.ErrorFormats, "")
+	app.Run([]string{"--error-format", "json", "-_"})
+	if strings.HasPrefix(b.String(), "{") {
+		t.Errorf("unexpected output: %q", b.String())
+	}
+}
+
+type exitCoder struct{}
+
+func (exitCoder) Error() string { return "exit coder" }
+func (exitCoder) ExitCode() int { return 3 }
+
+var exitCodeTests = []struct {
+	err  error
+	code int
+}{
+	{nil, 0},
+	{cli.Abort{Err: fmt.Errorf("abort")}, 1},
+	{cli.CommandError{Name: "cmd"}, 2},
+	{cli.FlagError("flag error"), 2},
+	{cli.ErrArgs, 2},
+	{cli.ErrCommand, 2},
+	{cli.Interrupt{}, 130},
+	{exitCoder{}, 3},
+	{fmt.Errorf("error"), 1},
+	{fmt.Errorf("wrapped: %w", cli.FlagError("flag error")), 2},
+	{fmt.Errorf("wrapped: %w", cli.ErrArgs), 2},
+	{fmt.Errorf("wrapped: %w", cli.Interrupt{}), 130},
+	{errors.Join(fmt.Errorf("error"), exitCoder{}), 3},
+}
+
+func TestExitCode(t *testing.T) {
+	for _, tt := range exitCodeTests {
+		if g, e := cli.ExitCode(tt.err), tt.code; g != e {
+			t.Errorf("ExitCode(%#v) = %v, expected %v", tt.err, g, e)
+		}
+	}
+}
+
+func TestCLIMain(t *testing.T) {
+	for _, tt := range exitCodeTests {
+		code := -1
+		app := cli.NewCLI()
+		app.Stdout = io.Discard
+		app.Stderr = io.Discard
+		app.Exit = func(i int) { code = i }
+		app.Action = func(*cli.Context) error { return tt.err }
+		app.Main(nil)
+		if g, e := code, tt.code; g != e {
+			t.Errorf("exit code = %v, expected %v", g, e)
+		}
+	}
+}
+
 func testOut(g, e string) error {
 	if g != e {
 		return fmt.Errorf("output differ\nexpected: %q\n     got: %q", e, g)
@@ -1,7 +1,7 @@
 //
 // go.cli :: cli_unix.go
 //
-//   Copyright (c) 2014-2023 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -11,7 +11,12 @@
 package cli
 
 import (
+	"context"
+	"io/fs"
+	"net"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -40,9 +45,56 @@ func init() {
 
 func (ui *CLI) title(title string) error {
 	if f, ok := ui.Stdout.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
-		if xtermRx.MatchString(os.Getenv("TERM")) {
+		if xtermRx.MatchString(ui.Getenv("TERM")) {
 			ui.Printf("\x1b]2;%v\a", title)
 		}
 	}
 	return nil
 }
+
+func shellCommand(ctx context.Context, name, s string, args []string) *exec.Cmd {
+	return exec.CommandContext(ctx, "/bin/sh", append([]string{"-c", s + ` "$@"`, name}, args...)...)
+}
+
+func isExecutable(fi fs.FileInfo) bool {
+	return fi.Mode().IsRegular() && fi.Mode()&0o111 != 0
+}
+
+func findExecutable(dir, name string) (string, bool) {
+	path := filepath.Join(dir, name)
+	if fi, err := os.Stat(path); err == nil && isExecutable(fi) {
+		return path, true
+	}
+	return "", false
+}
+
+func pluginName(name string) (string, bool) {
+	return name, true
+}
+
+// listenUnix listens on the Unix domain socket path which is accessible only
+// by the owner. The socket is created in a private directory, and moved to
+// path after its permissions are restricted.
+func listenUnix(path string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".cli-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "sock")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	ul := l.(*net.UnixListener)
+	ul.SetUnlinkOnClose(false)
+	if err = os.Chmod(tmp, 0o600); err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		ul.Close()
+		return nil, err
+	}
+	return ul, nil
+}
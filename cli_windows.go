@@ -1,7 +1,7 @@
 //
 // go.cli :: cli_windows.go
 //
-//   Copyright (c) 2014-2020 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -9,6 +9,13 @@
 package cli
 
 import (
+	"context"
+	"io/fs"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"unsafe"
 
@@ -40,3 +47,111 @@ func setConsoleTitle(title *uint16) (err error) {
 	}
 	return
 }
+
+func shellCommand(ctx context.Context, _, s string, args []string) *exec.Cmd {
+	comspec := os.Getenv("ComSpec")
+	if comspec == "" {
+		comspec = "cmd.exe"
+	}
+	var b strings.Builder
+	b.WriteString("/d /s /c \"")
+	b.WriteString(s)
+	for _, a := range args {
+		b.WriteRune(' ')
+		b.WriteString(syscall.EscapeArg(a))
+	}
+	b.WriteRune('"')
+	cmd := exec.CommandContext(ctx, comspec)
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: b.String()}
+	return cmd
+}
+
+func isExecutable(fi fs.FileInfo) bool {
+	return fi.Mode().IsRegular()
+}
+
+func findExecutable(dir, name string) (string, bool) {
+	for _, ext := range pathExt() {
+		path := filepath.Join(dir, name+ext)
+		if fi, err := os.Stat(path); err == nil && isExecutable(fi) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+func pluginName(name string) (string, bool) {
+	ext := filepath.Ext(name)
+	for _, e := range pathExt() {
+		if strings.EqualFold(ext, e) {
+			return name[:len(name)-len(ext)], true
+		}
+	}
+	return "", false
+}
+
+func pathExt() []string {
+	var list []string
+	for _, e := range strings.Split(strings.ToLower(os.Getenv("PATHEXT")), ";") {
+		if e != "" && e[0] == '.' {
+			list = append(list, e)
+		}
+	}
+	if len(list) == 0 {
+		list = []string{".com", ".exe", ".bat", ".cmd"}
+	}
+	return list
+}
+
+// listenUnix listens on the Unix domain socket path which is accessible only
+// by the owner. The socket is created in a private directory, and moved to
+// path after its DACL is restricted.
+func listenUnix(path string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".cli-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := setPrivateDACL(dir); err != nil {
+		return nil, err
+	}
+
+	tmp := filepath.Join(dir, "sock")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	ul := l.(*net.UnixListener)
+	ul.SetUnlinkOnClose(false)
+	if err = setPrivateDACL(tmp); err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		ul.Close()
+		return nil, err
+	}
+	return ul, nil
+}
+
+// setPrivateDACL replaces the DACL of name with the one which grants access
+// only to the current user.
+func setPrivateDACL(name string) error {
+	u, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return err
+	}
+	acl, err := windows.ACLFromEntries([]windows.EXPLICIT_ACCESS{{
+		AccessPermissions: windows.GENERIC_ALL,
+		AccessMode:        windows.GRANT_ACCESS,
+		Inheritance:       windows.SUB_CONTAINERS_AND_OBJECTS_INHERIT,
+		Trustee: windows.TRUSTEE{
+			TrusteeForm:  windows.TRUSTEE_IS_SID,
+			TrusteeType:  windows.TRUSTEE_IS_USER,
+			TrusteeValue: windows.TrusteeValueFromSID(u.User.Sid),
+		},
+	}}, nil)
+	if err != nil {
+		return err
+	}
+	return windows.SetNamedSecurityInfo(name, windows.SE_FILE_OBJECT, windows.DACL_SECURITY_INFORMATION|windows.PROTECTED_DACL_SECURITY_INFORMATION, nil, nil, acl, nil)
+}
@@ -0,0 +1,147 @@
+//
+// go.cli/clitest :: clitest.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package clitest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hattya/go.cli"
+)
+
+var Update = os.Getenv("CLITEST_UPDATE") != ""
+
+func CheckSchema(t testing.TB, ui *cli.CLI, golden string) {
+	t.Helper()
+
+	var b bytes.Buffer
+	if err := cli.WriteSchema(&b, ui); err != nil {
+		t.Fatal(err)
+	}
+	if Update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, b.Bytes(), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(golden)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		t.Fatalf("%v does not exist; set CLITEST_UPDATE=1 to create it", golden)
+	case err != nil:
+		t.Fatal(err)
+	}
+	e := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	g := strings.Split(b.String(), "\n")
+	for i := 0; i < len(e) || i < len(g); i++ {
+		var el, gl string
+		if i < len(e) {
+			el = e[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if el != gl {
+			t.Errorf("schema differs from %v at line %v\nexpected: %q\n     got: %q", golden, i+1, el, gl)
+			return
+		}
+	}
+}
+
+func CheckExamples(t testing.TB, ui *cli.CLI) {
+	t.Helper()
+
+	for _, ex := range ui.Examples {
+		if err := parseExample(ui, nil, ex.Cmd); err != nil {
+			t.Errorf("example %q: %v", ex.Cmd, err)
+		}
+	}
+	var walk func([]*cli.Command, []*cli.Command)
+	walk = func(stack, cmds []*cli.Command) {
+		for _, c := range cmds {
+			stack := append(stack[:len(stack):len(stack)], c)
+			for _, ex := range c.Examples {
+				if err := parseExample(ui, stack, ex.Cmd); err != nil {
+					t.Errorf("example %q: %v", ex.Cmd, err)
+				}
+			}
+			walk(stack, c.Cmds)
+		}
+	}
+	walk(nil, ui.Cmds)
+}
+
+func parseExample(ui *cli.CLI, path []*cli.Command, line string) error {
+	args, err := cli.Split(line)
+	switch {
+	case err != nil:
+		return err
+	case len(args) == 0:
+		return errors.New("empty command line")
+	}
+	args = args[1:]
+
+	// parse into copies of the flags to leave them untouched
+	parse := func(stack []*cli.Command) error {
+		fs := cli.NewFlagSet()
+		if ui.Flags != nil {
+			ui.Flags.Clone().VisitAll(fs.Add)
+		}
+		if fs.Lookup("h") == nil && fs.Lookup("help") == nil {
+			fs.Bool("h, help", false, "")
+		}
+		if fs.Lookup("version") == nil {
+			fs.Bool("version", false, "")
+		}
+		for _, c := range stack {
+			if c.Flags != nil {
+				c.Flags.Clone().VisitAll(fs.Add)
+			}
+		}
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		args = fs.Args()
+		return nil
+	}
+
+	if err := parse(nil); err != nil {
+		return err
+	}
+	var stack []*cli.Command
+	for cmds := ui.Cmds; len(cmds) > 0 && len(args) > 0; {
+		cmd, err := cli.FindCommand(cmds, args[0])
+		if err != nil {
+			return err
+		}
+		args = args[1:]
+		stack = append(stack, cmd)
+		if cmd.Flags != nil {
+			if err := parse(stack); err != nil {
+				return err
+			}
+		}
+		cmds = cmd.Cmds
+	}
+	for i, c := range path {
+		if len(stack) <= i || stack[i] != c {
+			return fmt.Errorf("command '%v' is not invoked", c.Name[0])
+		}
+	}
+	return nil
+}
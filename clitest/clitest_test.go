@@ -0,0 +1,114 @@
+//
+// go.cli/clitest :: clitest_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package clitest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hattya/go.cli"
+	"github.com/hattya/go.cli/clitest"
+)
+
+func TestCheckSchema(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "schema.json")
+	app := cli.NewCLI()
+	app.Add(&cli.Command{
+		Name: []string{"cmd"},
+	})
+
+	update := clitest.Update
+	defer func() { clitest.Update = update }()
+
+	clitest.Update = true
+	clitest.CheckSchema(t, app, golden)
+	if _, err := os.Stat(golden); err != nil {
+		t.Fatal(err)
+	}
+
+	clitest.Update = false
+	clitest.CheckSchema(t, app, golden)
+
+	app.Cmds[0].Desc = "desc"
+	if !failed(func(t testing.TB) { clitest.CheckSchema(t, app, golden) }) {
+		t.Error("expected failure")
+	}
+}
+
+func TestCheckExamples(t *testing.T) {
+	setup := func() *cli.CLI {
+		app := cli.NewCLI()
+		app.Flags.Bool("v", false, "")
+		app.Flags.String("m", "", "")
+		app.Add(&cli.Command{
+			Name:  []string{"cmd"},
+			Flags: cli.NewFlagSet(),
+			Cmds: []*cli.Command{
+				{
+					Name:  []string{"subcmd"},
+					Flags: cli.NewFlagSet(),
+				},
+			},
+		})
+		app.Cmds[0].Flags.Int("n", 0, "")
+		return app
+	}
+
+	app := setup()
+	app.Examples = []cli.Example{
+		{Cmd: "app -v"},
+		{Cmd: "app --help"},
+		{Cmd: "app cmd -n 1"},
+		{Cmd: `app -m "a message" cmd -n '1'`},
+	}
+	app.Cmds[0].Examples = []cli.Example{
+		{Cmd: "app cmd -n 1"},
+		{Cmd: "app -v c subcmd -n 1"},
+	}
+	app.Cmds[0].Cmds[0].Examples = []cli.Example{
+		{Cmd: "app cmd subcmd"},
+	}
+	clitest.CheckExamples(t, app)
+	if app.Flags.Get("m").(string) != "" || app.Cmds[0].Flags.Get("n").(int) != 0 {
+		t.Error("flags are modified")
+	}
+
+	for _, ex := range []string{
+		"",
+		"app -_",
+		"app _",
+		"app cmd -n _",
+		"app -n 1 cmd",
+		`app -m "a message cmd`,
+	} {
+		app := setup()
+		app.Examples = []cli.Example{{Cmd: ex}}
+		if !failed(func(t testing.TB) { clitest.CheckExamples(t, app) }) {
+			t.Errorf("expected failure: %q", ex)
+		}
+	}
+
+	app = setup()
+	app.Cmds[0].Cmds[0].Examples = []cli.Example{{Cmd: "app cmd"}}
+	if !failed(func(t testing.TB) { clitest.CheckExamples(t, app) }) {
+		t.Error("expected failure")
+	}
+}
+
+func failed(fn func(testing.TB)) bool {
+	t := new(testing.T)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(t)
+	}()
+	<-done
+	return t.Failed()
+}
@@ -0,0 +1,133 @@
+//
+// go.cli :: color.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+type ColorMode int
+
+const (
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+var ColorModes = map[string]any{
+	"auto":   ColorAuto,
+	"always": ColorAlways,
+	"never":  ColorNever,
+}
+
+func (m ColorMode) String() string {
+	switch m {
+	case ColorAlways:
+		return "always"
+	case ColorNever:
+		return "never"
+	}
+	return "auto"
+}
+
+type Style string
+
+func (s Style) Render(str string) string {
+	if s == "" || str == "" {
+		return str
+	}
+	return "\x1b[" + string(s) + "m" + str + "\x1b[0m"
+}
+
+type Theme struct {
+	Heading Style
+	Command Style
+	Flag    Style
+	MetaVar Style
+	Error   Style
+}
+
+var DefaultTheme = &Theme{
+	Heading: "1",
+	Command: "36",
+	Flag:    "32",
+	MetaVar: "33",
+	Error:   "1;31",
+}
+
+func (fs *FlagSet) ColorFlag(usage string) *Flag {
+	if usage == "" {
+		usage = "when to use colors: auto, always or never"
+	}
+	f := fs.Choice("color", ColorAuto, ColorModes, usage)
+	f.MetaVar = " <when>"
+	return f
+}
+
+func (ctx *Context) Color(w io.Writer) bool {
+	mode := ctx.UI.Color
+	// the flag overrides CLI.Color only when it was specified
+	for _, fs := range []*FlagSet{ctx.Flags, ctx.UI.Flags} {
+		if fs == nil {
+			continue
+		}
+		if f := fs.Lookup("color"); f != nil && fs.changed(f) {
+			if v, ok := f.Value.Get().(ColorMode); ok {
+				mode = v
+			}
+			break
+		}
+	}
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if ctx.UI.Getenv("NO_COLOR") != "" || ctx.UI.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+func (ctx *Context) Theme(w io.Writer) *Theme {
+	switch {
+	case !ctx.Color(w):
+		return new(Theme)
+	case ctx.UI.Theme != nil:
+		return ctx.UI.Theme
+	}
+	return DefaultTheme
+}
+
+var isTerminal = func(w io.Writer) bool {
+	if p, ok := w.(*pager); ok {
+		w = p.out
+	}
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// skipEscape returns the length of the ANSI escape sequence at the start
+// of s.
+func skipEscape(s string) int {
+	if !strings.HasPrefix(s, "\x1b[") {
+		return 0
+	}
+	for i := 2; i < len(s); i++ {
+		if 0x40 <= s[i] && s[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,166 @@
+//
+// go.cli :: color_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hattya/go.cli"
+)
+
+func TestStyle(t *testing.T) {
+	if g, e := cli.Style("").Render("s"), "s"; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+	if g, e := cli.Style("1").Render(""), ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+	if g, e := cli.Style("1;31").Render("s"), "\x1b[1;31ms\x1b[0m"; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+	if g, e := cli.StringWidth(cli.Style("1").Render("s")), 1; g != e {
+		t.Errorf("expected %v, got %v", e, g)
+	}
+}
+
+var colorTests = []struct {
+	mode    cli.ColorMode
+	flag    string
+	noColor string
+	out     bool
+}{
+	{cli.ColorAuto, "", "", false},
+	{cli.ColorAlways, "", "", true},
+	{cli.ColorAlways, "", "1", true},
+	{cli.ColorNever, "", "", false},
+	{cli.ColorNever, "always", "", true},
+	{cli.ColorAlways, "never", "", false},
+	{cli.ColorAlways, "auto", "", false},
+}
+
+func TestColor(t *testing.T) {
+	for _, tt := range colorTests {
+		t.Setenv("NO_COLOR", tt.noColor)
+
+		app := cli.NewCLI()
+		app.Color = tt.mode
+		app.Stdout = io.Discard
+		if tt.flag != "" {
+			app.Flags.Choice("color", cli.ColorAuto, cli.ColorModes, "")
+			if err := app.Flags.Set("color", tt.flag); err != nil {
+				t.Fatal(err)
+			}
+		}
+		ctx := cli.NewContext(app)
+		if g, e := ctx.Color(app.Stdout), tt.out; g != e {
+			t.Errorf("Context.Color() = %v, expected %v", g, e)
+		}
+		th := ctx.Theme(app.Stdout)
+		if g, e := *th != (cli.Theme{}), tt.out; g != e {
+			t.Errorf("Context.Theme() = %#v", th)
+		}
+	}
+}
+
+func TestColorFlag(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Color = cli.ColorAlways
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Flags.ColorFlag("")
+	var color []bool
+	app.Action = func(ctx *cli.Context) error {
+		color = append(color, ctx.Color(ctx.UI.Stdout))
+		return nil
+	}
+	for _, args := range [][]string{
+		{},
+		{"--color=never"},
+		{"--color=always"},
+		{"--color=auto"},
+	} {
+		if err := app.Run(args); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if g, e := color, []bool{true, false, true, false}; !reflect.DeepEqual(g, e) {
+		t.Errorf("expected %v, got %v", e, g)
+	}
+
+	if err := app.Run([]string{"--color=never", "--help"}); err != nil {
+		t.Fatal(err)
+	}
+	out := cli.Dedent(`
+		usage: %[1]v
+
+		options:
+
+		  --color <when>    when to use colors: auto, always or never
+		  -h, --help        show help
+		  --version         show version information
+
+	`)
+	if err := testOut(b.String(), fmt.Sprintf(out, app.Name)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestColorHelp(t *testing.T) {
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Color = cli.ColorAlways
+	app.Theme = &cli.Theme{
+		Heading: "1",
+		Command: "2",
+		Flag:    "3",
+		MetaVar: "4",
+		Error:   "5",
+	}
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Flags.String("s", "", "string")
+	app.Add(&cli.Command{
+		Name: []string{"cmd"},
+		Desc: "desc",
+	})
+	if err := app.Run([]string{"--help"}); err != nil {
+		t.Fatal(err)
+	}
+	out := strings.ReplaceAll(cli.Dedent(`
+		usage: %[1]v
+
+		\x1b[1mcommands:\x1b[0m
+
+		  \x1b[2mcmd\x1b[0m    desc
+
+		\x1b[1moptions:\x1b[0m
+
+		  \x1b[3m-h\x1b[0m, \x1b[3m--help\x1b[0m    show help
+		  \x1b[3m-s\x1b[0m \x1b[4m<s>\x1b[0m        string
+		  \x1b[3m--version\x1b[0m     show version information
+
+	`), `\x1b`, "\x1b")
+	if err := testOut(b.String(), fmt.Sprintf(out, app.Name)); err != nil {
+		t.Error(err)
+	}
+
+	b.Reset()
+	cli.ErrorHandler(cli.NewContext(app), fmt.Errorf("error"))
+	if err := testOut(b.String(), fmt.Sprintf("\x1b[5m%v:\x1b[0m error\n", app.Name)); err != nil {
+		t.Error(err)
+	}
+}
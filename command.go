@@ -1,7 +1,7 @@
 //
 // go.cli :: command.go
 //
-//   Copyright (c) 2014-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -15,25 +15,30 @@ import (
 )
 
 type Command struct {
-	Name   []string
-	Usage  any
-	Desc   string
-	Epilog string
-	Cmds   []*Command
-	Flags  *FlagSet
-	Action func(*Context) error
-	Data   any
+	Name     []string
+	Usage    any
+	Desc     string
+	Epilog   string
+	Examples []Example
+	Cmds     []*Command
+	Flags    *FlagSet
+	Action   func(*Context) error
+	Data     any
+	Hidden   bool
+
+	help bool
 }
 
 func (c *Command) Run(ctx *Context) error {
 	if c.Flags != nil {
+		// the flags are already set from their environment variables
 		ctx.Flags = NewFlagSet()
-		ctx.UI.Flags.VisitAll(ctx.Flags.Add)
+		ctx.UI.Flags.VisitAll(ctx.Flags.add)
 		for _, cmd := range ctx.Stack {
 			if cmd.Flags == nil {
 				panic(ErrFlags)
 			}
-			cmd.Flags.VisitAll(ctx.Flags.Add)
+			ctx.UI.flagSet(cmd.Flags).VisitAll(ctx.Flags.add)
 		}
 		if err := ctx.Flags.Parse(ctx.Args); err != nil {
 			return err
@@ -41,7 +46,7 @@ func (c *Command) Run(ctx *Context) error {
 		ctx.Args = ctx.Flags.Args()
 		switch {
 		case ctx.UI.help && ctx.Bool("help"):
-			return Help(ctx)
+			return showHelp(ctx)
 		case ctx.UI.version && ctx.Bool("version"):
 			return Version(ctx)
 		}
@@ -68,7 +73,7 @@ L:
 			}
 		}
 		// prefix match
-		if name != "" {
+		if name != "" && !c.Hidden {
 			for _, n := range c.Name {
 				if strings.HasPrefix(n, name) {
 					set[n] = c
@@ -104,6 +109,11 @@ L:
 	return
 }
 
+type Example struct {
+	Cmd  string `json:"cmd"`
+	Desc string `json:"desc,omitempty"`
+}
+
 type CommandError struct {
 	Name string
 	List []string
@@ -116,6 +126,8 @@ func (e CommandError) Error() string {
 	return fmt.Sprintf("command '%v' is ambiguous (%v)", e.Name, strings.Join(e.List, ", "))
 }
 
+func (e CommandError) ExitCode() int { return 2 }
+
 type CommandSlice []*Command
 
 func (p CommandSlice) Len() int           { return len(p) }
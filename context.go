@@ -1,7 +1,7 @@
 //
 // go.cli :: context.go
 //
-//   Copyright (c) 2014-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -10,6 +10,9 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"time"
 )
@@ -22,6 +25,10 @@ type Context struct {
 	Flags *FlagSet
 	Args  []string
 	Data  any
+
+	cleanups []func() error
+	// nested reports whether Cmds are subcommands of a command
+	nested bool
 }
 
 func NewContext(ui *CLI) *Context {
@@ -47,18 +54,66 @@ func (ctx *Context) Name() string {
 }
 
 func (ctx *Context) Command() (cmd *Command, err error) {
-	switch {
-	case len(ctx.Cmds) == 0:
-	case len(ctx.Args) == 0:
-		err = ErrCommand
-	default:
-		cmd, err = FindCommand(ctx.Cmds, ctx.Args[0])
+	var seen map[string]bool
+	for {
+		switch {
+		case len(ctx.Cmds) == 0:
+			return
+		case len(ctx.Args) == 0:
+			return nil, ErrCommand
+		}
+		name := ctx.Args[0]
+		cmd, err = FindCommand(ctx.Cmds, name)
+		if s, ok := ctx.UI.Aliases[name]; ok && !slices.Contains(nameOf(cmd), name) && ctx.topLevel() {
+			// user-defined alias
+			if seen[name] {
+				return nil, fmt.Errorf("alias '%v' is recursive", name)
+			} else if seen == nil {
+				seen = make(map[string]bool)
+			}
+			seen[name] = true
+			if sh, ok := strings.CutPrefix(s, "!"); ok {
+				ctx.Cmds = nil
+				ctx.Args = ctx.Args[1:]
+				return shellAlias(name, sh), nil
+			}
+			args, err := Split(s)
+			switch {
+			case err != nil:
+				return nil, fmt.Errorf("alias '%v': %w", name, err)
+			case len(args) == 0:
+				return nil, fmt.Errorf("alias '%v' is empty", name)
+			}
+			ctx.Args = append(args, ctx.Args[1:]...)
+			continue
+		}
+		var cmdErr CommandError
+		if errors.As(err, &cmdErr) && len(cmdErr.List) == 0 && ctx.topLevel() {
+			// external command
+			if path, ok := ctx.UI.lookPlugin(name); ok {
+				ctx.Cmds = nil
+				ctx.Args = ctx.Args[1:]
+				return pluginCommand(name, path), nil
+			}
+		}
 		if err == nil {
 			ctx.Cmds = cmd.Cmds
 			ctx.Args = ctx.Args[1:]
+			ctx.nested = true
 		}
+		return
+	}
+}
+
+func (ctx *Context) topLevel() bool {
+	return !ctx.nested
+}
+
+func nameOf(cmd *Command) []string {
+	if cmd == nil {
+		return nil
 	}
-	return
+	return cmd.Name
 }
 
 func (ctx *Context) Bool(name string) bool {
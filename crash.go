@@ -0,0 +1,129 @@
+//
+// go.cli :: crash.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+type InternalError struct {
+	Value  any
+	Stack  []byte
+	Report string
+}
+
+func (e InternalError) Error() string { return fmt.Sprintf("internal error: %v", e.Value) }
+func (e InternalError) ExitCode() int { return 70 }
+
+func (ui *CLI) action(ctx *Context, args []string) (err error) {
+	if ui.Recover {
+		defer func() {
+			if v := recover(); v != nil {
+				err = ui.crash(ctx, args, v, debug.Stack())
+			}
+		}()
+	}
+	return ui.Action(ctx)
+}
+
+func (ui *CLI) crash(ctx *Context, args []string, v any, stack []byte) error {
+	err := InternalError{
+		Value: v,
+		Stack: stack,
+	}
+	dir, derr := os.UserCacheDir()
+	if derr != nil {
+		return err
+	}
+	dir = filepath.Join(dir, ui.Name)
+	if os.MkdirAll(dir, 0o777) != nil {
+		return err
+	}
+	f, ferr := os.CreateTemp(dir, time.Now().Format("crash-20060102-150405-*.txt"))
+	if ferr != nil {
+		return err
+	}
+	defer f.Close()
+
+	version := ui.Version
+	if version == "" {
+		version = "unknown"
+	}
+	fmt.Fprintf(f, "%v version %v\n", ui.Name, version)
+	fmt.Fprintf(f, "command: %v\n", ctx.Name())
+	fmt.Fprintf(f, "args: %q\n", sanitize(ctx, args))
+	fmt.Fprintf(f, "go: %v %v/%v\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(f, "\npanic: %v\n\n%s", v, stack)
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(f, "\n%v", bi)
+	}
+	if f.Close() == nil {
+		err.Report = f.Name()
+	}
+	return err
+}
+
+var sensitive = []string{
+	"auth",
+	"credential",
+	"key",
+	"pass",
+	"secret",
+	"token",
+}
+
+func sanitize(ctx *Context, args []string) []string {
+	isSensitive := func(n string) bool {
+		n = strings.ToLower(n)
+		for _, s := range sensitive {
+			if strings.Contains(n, s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	list := make([]string, len(args))
+	redact := false
+	flags := true
+	for i, a := range args {
+		switch {
+		case redact:
+			a = "REDACTED"
+			redact = false
+		case a == "--":
+			flags = false
+		case flags && strings.HasPrefix(a, "-"):
+			n, _, ok := strings.Cut(strings.TrimLeft(a, "-"), "=")
+			if isSensitive(n) {
+				if ok {
+					a = a[:strings.IndexByte(a, '=')+1] + "REDACTED"
+				} else if f := ctx.Flags.Lookup(n); f == nil || !f.IsBool() {
+					redact = true
+				}
+			}
+		default:
+			if u, err := url.Parse(a); err == nil && u.User != nil {
+				if _, ok := u.User.Password(); ok {
+					u.User = url.UserPassword(u.User.Username(), "REDACTED")
+					a = u.String()
+				}
+			}
+		}
+		list[i] = a
+	}
+	return list
+}
@@ -0,0 +1,205 @@
+//
+// go.cli :: editor.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const HistorySize = 1000
+
+type History = term.History
+
+func PromptHistory(name string) PromptOption {
+	return func(o *promptOptions) {
+		o.history = name
+	}
+}
+
+func PromptCompleter(fn func(string) []string) PromptOption {
+	return func(o *promptOptions) {
+		o.complete = fn
+	}
+}
+
+// editor is a line editor which is used while Stdin is a terminal.
+type editor struct {
+	lr      *lineReader
+	ctx     context.Context
+	uctx    context.Context
+	in      io.Reader
+	out     io.Writer
+	t       *term.Terminal
+	history History
+}
+
+func (ui *CLI) editor() *editor {
+	in := ui.input()
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if in.ed == nil || in.ed.in != ui.Stdin || in.ed.out != ui.Stdout {
+		ed := &editor{
+			in:  ui.Stdin,
+			out: ui.Stdout,
+		}
+		ed.t = term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{ed, ui.Stdout}, "")
+		ed.history = ed.t.History
+		in.ed = ed
+	}
+	return in.ed
+}
+
+func (ui *CLI) editLine(ctx context.Context, prompt string, o *promptOptions) (string, error) {
+	ed := ui.editor()
+	if f, ok := ui.Stdin.(*os.File); ok {
+		fd := int(f.Fd())
+		st, err := term.MakeRaw(fd)
+		if err != nil {
+			return "", err
+		}
+		defer term.Restore(fd, st)
+		if w, h, err := term.GetSize(fd); err == nil {
+			ed.t.SetSize(w, h)
+		}
+	}
+
+	ed.lr = ui.reader()
+	ed.ctx = ctx
+	ed.uctx = ui.ctx
+	ed.t.SetPrompt(prompt)
+	ed.t.History = ed.history
+	if o.history != "" {
+		ed.t.History = ui.loadHistory(o.history)
+	}
+	ed.t.AutoCompleteCallback = nil
+	if o.complete != nil {
+		ed.t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+			if key != '\t' {
+				return "", 0, false
+			}
+			s := complete(line[:pos], o.complete(line[:pos]))
+			return s + line[pos:], len(s), true
+		}
+	}
+	s, err := ed.t.ReadLine()
+	if err == term.ErrPasteIndicator {
+		err = nil
+	}
+	return s, err
+}
+
+// Read reads from Stdin through the buffered reader, and reports Ctrl-C as
+// Interrupt because it does not generate a signal in raw mode.
+func (ed *editor) Read(p []byte) (int, error) {
+	lr := ed.lr
+	b, err := lr.read(ed.ctx, ed.uctx)
+	if err != nil {
+		return 0, err
+	}
+	switch i := bytes.IndexByte(b, 0x03); i {
+	case -1:
+	case 0:
+		lr.unread(b[1:])
+		return 0, Interrupt{}
+	default:
+		lr.unread(b[i:])
+		b = b[:i]
+	}
+	n := copy(p, b)
+	lr.unread(b[n:])
+	return n, nil
+}
+
+func complete(prefix string, list []string) string {
+	var cands []string
+	for _, s := range list {
+		if strings.HasPrefix(s, prefix) {
+			cands = append(cands, s)
+		}
+	}
+	if len(cands) == 0 {
+		return prefix
+	}
+	s := cands[0]
+	for _, c := range cands[1:] {
+		i := 0
+		for i < len(s) && i < len(c) && s[i] == c[i] {
+			i++
+		}
+		s = s[:i]
+	}
+	return s
+}
+
+type history struct {
+	path  string
+	lines []string
+	n     int // number of lines in the file
+}
+
+func (ui *CLI) loadHistory(name string) *history {
+	h := new(history)
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return h
+	}
+	h.path = filepath.Join(dir, ui.Name, "history", name)
+	f, err := os.Open(h.path)
+	if err != nil {
+		return h
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		h.lines = append(h.lines, s.Text())
+	}
+	h.n = len(h.lines)
+	if len(h.lines) > HistorySize {
+		h.lines = h.lines[len(h.lines)-HistorySize:]
+	}
+	return h
+}
+
+func (h *history) Add(s string) {
+	if s == "" || strings.ContainsAny(s, "\r\n") || (len(h.lines) > 0 && h.lines[len(h.lines)-1] == s) {
+		return
+	}
+	h.lines = append(h.lines, s)
+	if len(h.lines) > HistorySize {
+		h.lines = h.lines[len(h.lines)-HistorySize:]
+	}
+	if h.path == "" || os.MkdirAll(filepath.Dir(h.path), 0o777) != nil {
+		return
+	}
+	if h.n < HistorySize {
+		if f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600); err == nil {
+			if _, err := f.WriteString(s + "\n"); err == nil {
+				h.n++
+			}
+			f.Close()
+		}
+	} else if os.WriteFile(h.path, []byte(strings.Join(h.lines, "\n")+"\n"), 0o600) == nil {
+		// rewrite the file with the last HistorySize lines
+		h.n = len(h.lines)
+	}
+}
+
+func (h *history) Len() int        { return len(h.lines) }
+func (h *history) At(i int) string { return h.lines[len(h.lines)-1-i] }
@@ -0,0 +1,198 @@
+//
+// go.cli :: editor_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hattya/go.cli"
+)
+
+var editLineTests = []struct {
+	in   string
+	line string
+}{
+	{"foo\r", "foo"},
+	{"fo\x7fo\r", "fo"},
+	{"foo\x02\x02x\r", "fxoo"},
+	{"foo\x01x\x05y\r", "xfooy"},
+	{"foo bar\x17baz\r", "foo baz"},
+	{"foo bar\x01\x06\x0b\r", "f"},
+	{"foo\x15bar\r", "bar"},
+}
+
+func TestEditLine(t *testing.T) {
+	var stdin bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdin = &stdin
+	app.Stdout = io.Discard
+	for _, tt := range editLineTests {
+		stdin.Reset()
+
+		stdin.WriteString(tt.in)
+		line, err := cli.EditLine(app, "> ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g, e := line, tt.line; g != e {
+			t.Errorf("editLine(%q) = %q, expected %q", tt.in, g, e)
+		}
+	}
+
+	// read ahead
+	stdin.WriteString("foo\rbar\r")
+	for _, e := range []string{"foo", "bar"} {
+		line, err := cli.EditLine(app, "> ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g := line; g != e {
+			t.Errorf("editLine() = %q, expected %q", g, e)
+		}
+	}
+
+	for _, tt := range []struct {
+		in  string
+		err error
+	}{
+		{"foo\x03", cli.Interrupt{}},
+		{"\x04", io.EOF},
+		{"", io.EOF},
+	} {
+		stdin.Reset()
+
+		stdin.WriteString(tt.in)
+		if _, err := cli.EditLine(app, "> "); err != tt.err {
+			t.Errorf("expected %#v, got %#v", tt.err, err)
+		}
+	}
+}
+
+func TestEditLineHistory(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("LocalAppData", dir)
+
+	var stdin bytes.Buffer
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Stdin = &stdin
+	app.Stdout = io.Discard
+	stdin.WriteString("foo\rbar\rbar\r\r")
+	for range 4 {
+		if _, err := cli.EditLine(app, "> ", cli.PromptHistory("test")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(cache, "app", "history", "test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := string(b), "foo\nbar\n"; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+
+	app = cli.NewCLI()
+	app.Name = "app"
+	app.Stdin = &stdin
+	app.Stdout = io.Discard
+	stdin.WriteString("\x1b[A\x1b[A\r")
+	line, err := cli.EditLine(app, "> ", cli.PromptHistory("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := line, "foo"; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+
+	// history of another prompt
+	stdin.WriteString("\x1b[A\r")
+	line, err = cli.EditLine(app, "> ", cli.PromptHistory("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := line, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+
+	// the file is rewritten when it is longer than HistorySize
+	path := filepath.Join(cache, "app", "history", "long")
+	var lines []string
+	for i := range cli.HistorySize + 10 {
+		lines = append(lines, fmt.Sprint(i))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	stdin.WriteString("end\r")
+	if _, err := cli.EditLine(app, "> ", cli.PromptHistory("long")); err != nil {
+		t.Fatal(err)
+	}
+	b, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines = append(lines[len(lines)-cli.HistorySize+1:], "end")
+	if g, e := string(b), strings.Join(lines, "\n")+"\n"; g != e {
+		t.Errorf("expected %v lines, got %v lines", len(lines), strings.Count(g, "\n"))
+	}
+}
+
+func TestEditLineCompleter(t *testing.T) {
+	var stdin bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdin = &stdin
+	app.Stdout = io.Discard
+	complete := cli.PromptCompleter(func(string) []string {
+		return []string{"bar", "foobar", "foobaz"}
+	})
+	for _, tt := range []struct {
+		in   string
+		line string
+	}{
+		{"f\t\r", "fooba"},
+		{"b\t\r", "bar"},
+		{"x\t\r", "x"},
+		{"fx\x02\t\r", "foobax"},
+	} {
+		stdin.Reset()
+
+		stdin.WriteString(tt.in)
+		line, err := cli.EditLine(app, "> ", complete)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g, e := line, tt.line; g != e {
+			t.Errorf("editLine(%q) = %q, expected %q", tt.in, g, e)
+		}
+	}
+}
+
+func TestEditLineInterrupt(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	app := cli.NewCLI()
+	app.Stdin = r
+	app.Stdout = io.Discard
+	app.Interrupt()
+	if _, err := cli.EditLine(app, "> "); err != (cli.Interrupt{}) {
+		t.Errorf("expected Interrupt, got %#v", err)
+	}
+}
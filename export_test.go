@@ -0,0 +1,20 @@
+//
+// go.cli :: export_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+var (
+	IsTerminal = &isTerminal
+
+	Menu            = (*CLI).menu
+	CompleteCommand = (*CLI).completeCommand
+)
+
+func EditLine(ui *CLI, prompt string, opts ...PromptOption) (string, error) {
+	return ui.editLine(ui.Context(), prompt, newPromptOptions(opts))
+}
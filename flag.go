@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
@@ -25,8 +26,20 @@ type Flag struct {
 	Default string
 	MetaVar string
 	EnvVar  string
+
+	role flagRole
 }
 
+// flagRole is the role of a flag which is handled by the CLI.
+type flagRole int
+
+const (
+	roleNone flagRole = iota
+	roleScript
+	roleYes
+	roleErrorFormat
+)
+
 func (f *Flag) IsBool() bool {
 	if b, ok := f.Value.(boolFlag); ok {
 		return b.IsBoolFlag()
@@ -35,19 +48,25 @@ func (f *Flag) IsBool() bool {
 }
 
 func (f *Flag) Format(sep string) string {
+	return f.format(sep, new(Theme))
+}
+
+func (f *Flag) format(sep string, th *Theme) string {
 	var b strings.Builder
 	for i, n := range f.Name {
 		if i > 0 {
 			b.WriteString(", ")
 		}
 		if len(n) == 1 {
-			b.WriteRune('-')
+			b.WriteString(th.Flag.Render("-" + n))
 		} else {
-			b.WriteString("--")
+			b.WriteString(th.Flag.Render("--" + n))
 		}
-		b.WriteString(n)
 	}
-	b.WriteString(MetaVar(f))
+	mv := MetaVar(f)
+	s := strings.TrimLeft(mv, " ")
+	b.WriteString(mv[:len(mv)-len(s)])
+	b.WriteString(th.MetaVar.Render(s))
 	if f.Usage != "" {
 		b.WriteString(sep)
 		if n, pct := f.numVerb(f.Usage); n > 0 && n != pct {
@@ -101,10 +120,99 @@ func NewFlagSet() *FlagSet {
 	return fs
 }
 
-func (fs *FlagSet) Parse(args []string) error { return fs.error(fs.fs.Parse(args)) }
+func (fs *FlagSet) Parse(args []string) error {
+	var errs Errors
+	var last string
+	for {
+		err := fs.fs.Parse(args)
+		if err == nil {
+			break
+		}
+		// continue parsing after the invalid flag
+		if rest := fs.fs.Args(); len(rest) < len(args) {
+			errs = append(errs, fs.error(err))
+			last = err.Error()
+			args = rest
+			continue
+		}
+		// a flag with bad syntax is not consumed, and it is reported again
+		// when it follows the consumed flags
+		if err.Error() != last {
+			errs = append(errs, fs.error(err))
+		}
+		last = ""
+		args = args[1:]
+	}
+	return errs.Err()
+}
+
+func (fs *FlagSet) Clone() *FlagSet {
+	c := NewFlagSet()
+	for _, f := range fs.list {
+		nf := *f
+		nf.Name = append([]string(nil), f.Name...)
+		nf.Value = cloneValue(f.Value)
+		for _, n := range nf.Name {
+			c.fs.Var(nf.Value, n, nf.Usage)
+			c.vars[n] = &nf
+		}
+		c.list = append(c.list, &nf)
+	}
+	return c
+}
+
+type Cloner interface {
+	Clone() flag.Getter
+}
+
+// cloneValue returns a copy of v. A value which does not implement Cloner is
+// copied only if it is a pointer to a value without references, otherwise
+// it is shared by the copies.
+func cloneValue(v flag.Getter) flag.Getter {
+	if c, ok := v.(Cloner); ok {
+		return c.Clone()
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() && isPlain(rv.Elem().Type()) {
+		nv := reflect.New(rv.Elem().Type())
+		nv.Elem().Set(rv.Elem())
+		return nv.Interface().(flag.Getter)
+	}
+	return v
+}
+
+func isPlain(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return isPlain(t.Elem())
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if !isPlain(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
 
 func (fs *FlagSet) Lookup(name string) *Flag { return fs.vars[name] }
 
+// lookupRole returns the flag which has the specified role.
+func (fs *FlagSet) lookupRole(role flagRole) *Flag {
+	for _, f := range fs.list {
+		if f.role == role {
+			return f
+		}
+	}
+	return nil
+}
+
 func (fs *FlagSet) MetaVar(name, metaVar string) error {
 	f, ok := fs.vars[name]
 	if !ok {
@@ -164,6 +272,17 @@ func (fs *FlagSet) Visit(fn func(*Flag)) {
 	})
 }
 
+// changed reports whether the flag was set on the FlagSet, or its value
+// differs from the default (e.g. it was set from the environment variable).
+func (fs *FlagSet) changed(f *Flag) (set bool) {
+	fs.Visit(func(ff *Flag) {
+		if ff == f {
+			set = true
+		}
+	})
+	return set || f.Value.String() != f.Default
+}
+
 func (fs *FlagSet) VisitAll(fn func(*Flag)) {
 	list := make(sort.StringSlice, len(fs.list))
 	for i, f := range fs.list {
@@ -197,16 +316,34 @@ func (fs *FlagSet) NArg() int { return fs.fs.NArg() }
 func (fs *FlagSet) Parsed() bool { return fs.fs.Parsed() }
 
 func (fs *FlagSet) Add(f *Flag) {
+	fs.add(f)
+	if f.EnvVar != "" {
+		if s := os.Getenv(f.EnvVar); s != "" {
+			f.Value.Set(s)
+		}
+	}
+}
+
+// add adds the flag without setting it from the environment variable.
+func (fs *FlagSet) add(f *Flag) {
 	f.sort()
 	for _, n := range f.Name {
 		fs.fs.Var(f.Value, n, f.Usage)
 		fs.vars[n] = f
 	}
 	fs.list = append(fs.list, f)
+}
 
-	if f.EnvVar != "" {
-		if s := os.Getenv(f.EnvVar); s != "" {
-			f.Value.Set(s)
+// setEnv sets the flags which have environment variables to the values
+// returned by getenv, or their defaults.
+func (fs *FlagSet) setEnv(getenv func(string) string) {
+	for _, f := range fs.list {
+		if f.EnvVar != "" {
+			if s := getenv(f.EnvVar); s != "" {
+				f.Value.Set(s)
+			} else if f.Value.String() != f.Default {
+				f.Value.Set(f.Default)
+			}
 		}
 	}
 }
@@ -383,6 +520,11 @@ func (c *choiceValue) error(m map[string]any) error {
 	return FlagError(b.String())
 }
 
+func (c *choiceValue) Clone() flag.Getter {
+	nc := *c
+	return &nc
+}
+
 func (c *choiceValue) Get() any { return c.value }
 
 func (c *choiceValue) String() string { return fmt.Sprintf("%v", c.value) }
@@ -429,3 +571,4 @@ func (fs *FlagSet) each(name, envVar string, fn func(string)) *Flag {
 type FlagError string
 
 func (e FlagError) Error() string { return string(e) }
+func (e FlagError) ExitCode() int { return 2 }
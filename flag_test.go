@@ -9,7 +9,9 @@
 package cli_test
 
 import (
+	"flag"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -215,6 +217,77 @@ func TestResetFlags(t *testing.T) {
 	}
 }
 
+func TestCloneFlags(t *testing.T) {
+	flags := cli.NewFlagSet()
+	flags.Bool("h, help", false, "")
+	flags.Int("int", -1, "")
+	flags.Choice("choice", 1, map[string]any{"foo": 1, "bar": 2}, "")
+	flags.Var("var", new(value), "")
+	flags.Var("list", new(listValue), "")
+
+	c := flags.Clone()
+	if err := c.Parse(strings.Fields("-h -int 0 -choice bar -var var -list a -list b")); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name  string
+		value any
+	}{
+		{"help", false},
+		{"int", -1},
+		{"choice", 1},
+		{"var", ""},
+		{"list", ""},
+	} {
+		if g, e := flags.Get(tt.name), tt.value; g != e {
+			t.Errorf("FlagSet.Get(%q) = %v, expected %v", tt.name, g, e)
+		}
+	}
+	if g, e := c.Lookup("h"), c.Lookup("help"); g != e {
+		t.Errorf("FlagSet.Lookup(%q) = %p, expected %p", "h", g, e)
+	}
+	if g, e := c.Get("list"), "a,b"; g != e {
+		t.Errorf("FlagSet.Get(%q) = %v, expected %v", "list", g, e)
+	}
+
+	// values which cannot be copied are shared
+	flags = cli.NewFlagSet()
+	flags.Var("slice", new(sliceValue), "")
+	c = flags.Clone()
+	if err := c.Parse(strings.Fields("-slice a -slice b")); err != nil {
+		t.Fatal(err)
+	}
+	if g, e := flags.Lookup("slice").Value.String(), "a,b"; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+type listValue struct {
+	list []string
+}
+
+func (v *listValue) Clone() flag.Getter {
+	return &listValue{list: slices.Clone(v.list)}
+}
+
+func (v *listValue) Set(s string) error {
+	v.list = append(v.list, s)
+	return nil
+}
+
+func (v *listValue) Get() any       { return v.String() }
+func (v *listValue) String() string { return strings.Join(v.list, ",") }
+
+type sliceValue []string
+
+func (v *sliceValue) Set(s string) error {
+	*v = append(*v, s)
+	return nil
+}
+
+func (v *sliceValue) Get() any       { return []string(*v) }
+func (v *sliceValue) String() string { return strings.Join(*v, ",") }
+
 func TestVisitFlags(t *testing.T) {
 	flags := cli.NewFlagSet()
 	flags.Bool("h, help", false, "")
@@ -325,3 +398,43 @@ func TestPrefixChoiceFlag(t *testing.T) {
 		t.Error("unexpected error:", err)
 	}
 }
+
+func TestParseErrors(t *testing.T) {
+	flags := cli.NewFlagSet()
+	flags.Int("i", 0, "")
+	flags.Bool("b", false, "")
+	flags.Choice("c", 0, map[string]any{"foo": 1}, "")
+
+	err := flags.Parse([]string{"-x", "-i", "_", "-b", "---", "-=", "-c", "bar", "arg", "-y"})
+	errs, ok := err.(cli.Errors)
+	if !ok {
+		t.Fatalf("expected Errors, got %#v", err)
+	}
+	e := []string{
+		"flag provided but not defined: -x",
+		`invalid value "_" for flag -i: parse error`,
+		"bad flag syntax: ---",
+		"bad flag syntax: -=",
+		`invalid value "bar" for flag -c: choose from "foo"`,
+	}
+	if err := testStrings(func(i int) string { return errs[i].Error() }, e); err != nil {
+		t.Error(err)
+	}
+	for _, err := range errs {
+		if _, ok := err.(cli.FlagError); !ok {
+			t.Errorf("expected FlagError, got %#v", err)
+		}
+	}
+	if !flags.Lookup("b").Value.Get().(bool) {
+		t.Error("flags after an error are not parsed")
+	}
+	if err := testStrings(flags.Arg, []string{"arg", "-y"}); err != nil {
+		t.Error(err)
+	}
+
+	// single error
+	flags.Reset()
+	if _, ok := flags.Parse([]string{"-x"}).(cli.FlagError); !ok {
+		t.Errorf("expected FlagError, got %#v", err)
+	}
+}
@@ -1,7 +1,7 @@
 //
 // go.cli :: help.go
 //
-//   Copyright (c) 2014-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -9,9 +9,11 @@
 package cli
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
-	"text/tabwriter"
 	"text/template"
 )
 
@@ -22,14 +24,25 @@ func NewHelpCommand() *Command {
 		Desc:  "show help for a specified command",
 		Flags: NewFlagSet(),
 		Action: func(ctx *Context) error {
-			if len(ctx.Stack) > 1 {
-				ctx.Cmds = ctx.Stack[len(ctx.Stack)-2].Cmds
-			} else {
+			top := len(ctx.Stack) <= 1
+			if top {
 				ctx.Cmds = ctx.UI.Cmds
+			} else {
+				ctx.Cmds = ctx.Stack[len(ctx.Stack)-2].Cmds
 			}
 			ctx.Stack = nil
+			ctx.nested = !top
 			for len(ctx.Args) > 0 {
 				cmd, err := ctx.Command()
+				if errors.As(err, new(CommandError)) && top && len(ctx.Stack) == 0 && len(ctx.Args) == 1 {
+					// help topic
+					t, terr := FindTopic(ctx.UI.Topics, ctx.Args[0])
+					if terr == nil {
+						return showTopic(ctx, t)
+					} else if len(terr.(TopicError).List) > 0 {
+						err = terr
+					}
+				}
 				switch {
 				case err != nil:
 					return Abort{
@@ -41,8 +54,9 @@ func NewHelpCommand() *Command {
 				}
 				ctx.Stack = append(ctx.Stack, cmd)
 			}
-			return Help(ctx)
+			return showHelp(ctx)
 		},
+		help: true,
 	}
 }
 
@@ -52,14 +66,140 @@ var (
 	MetaVar = FormatMetaVar
 )
 
+func showHelp(ctx *Context) error {
+	if ctx.UI.PageHelp {
+		return ctx.Page(func() error { return Help(ctx) })
+	}
+	return Help(ctx)
+}
+
+func showTopic(ctx *Context, t *Topic) error {
+	if ctx.UI.PageHelp {
+		return ctx.Page(func() error { return ShowTopic(ctx, t) })
+	}
+	return ShowTopic(ctx, t)
+}
+
 func ShowHelp(ctx *Context) error {
 	t := template.Must(template.New("help").Funcs(FuncMap()).Parse(helpTmpl))
-	w := tabwriter.NewWriter(ctx.UI.Stdout, 0, 8, 4, ' ', 0)
+	w := newHelpWriter(ctx.UI.Stdout, width(ctx.UI.Stdout, ctx.UI.Getenv))
 	defer w.Flush()
 	return t.Execute(w, ctx)
 }
 
-const helpTmpl = `{{range usage . -}}
+// helpWriter aligns tab-separated cells like text/tabwriter, and wraps
+// lines which exceed the width.
+type helpWriter struct {
+	w     io.Writer
+	width int
+	buf   []byte
+	block [][2]string
+}
+
+func newHelpWriter(w io.Writer, width int) *helpWriter {
+	return &helpWriter{
+		w:     w,
+		width: width,
+	}
+}
+
+func (w *helpWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i == -1 {
+			break
+		}
+		l := string(w.buf[:i])
+		w.buf = w.buf[i+1:]
+		if err := w.line(l, true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *helpWriter) Flush() error {
+	if len(w.buf) > 0 {
+		l := string(w.buf)
+		w.buf = nil
+		if err := w.line(l, false); err != nil {
+			return err
+		}
+	}
+	return w.flushBlock()
+}
+
+func (w *helpWriter) line(l string, newline bool) error {
+	if cell, text, ok := strings.Cut(l, "\t"); ok && newline {
+		w.block = append(w.block, [2]string{cell, text})
+		return nil
+	}
+	if err := w.flushBlock(); err != nil {
+		return err
+	}
+	var b strings.Builder
+	if cell, text, ok := strings.Cut(l, "\t"); ok {
+		w.cells(&b, cell, StringWidth(cell)+4, text)
+	} else {
+		text = strings.TrimLeft(l, " \t")
+		ind := l[:len(l)-len(text)]
+		for i, s := range Wrap(text, w.width-StringWidth(ind)) {
+			if i > 0 {
+				b.WriteRune('\n')
+			}
+			b.WriteString(ind)
+			b.WriteString(s)
+		}
+	}
+	if newline {
+		b.WriteRune('\n')
+	}
+	_, err := io.WriteString(w.w, b.String())
+	return err
+}
+
+func (w *helpWriter) flushBlock() error {
+	if len(w.block) == 0 {
+		return nil
+	}
+	col := 0
+	for _, l := range w.block {
+		col = max(col, StringWidth(l[0]))
+	}
+	col += 4
+	var b strings.Builder
+	for _, l := range w.block {
+		w.cells(&b, l[0], col, l[1])
+		b.WriteRune('\n')
+	}
+	w.block = w.block[:0]
+	_, err := io.WriteString(w.w, b.String())
+	return err
+}
+
+func (w *helpWriter) cells(b *strings.Builder, cell string, col int, text string) {
+	b.WriteString(cell)
+	if text == "" {
+		return
+	}
+	b.WriteString(strings.Repeat(" ", col-StringWidth(cell)))
+	lines := []string{text}
+	// do not wrap into a too narrow column
+	if w.width-col >= 20 {
+		lines = Wrap(text, w.width-col)
+	}
+	for i, s := range lines {
+		if i > 0 {
+			b.WriteRune('\n')
+			b.WriteString(strings.Repeat(" ", col))
+		}
+		b.WriteString(s)
+	}
+}
+
+const helpTmpl = `{{$t := theme . -}}
+{{range usage . -}}
 {{.}}
 {{end}}
 {{- with or (cmd .) .UI -}}
@@ -68,30 +208,66 @@ const helpTmpl = `{{range usage . -}}
 {{end}}
 {{- range $i, $cmd := cmds .Cmds -}}
 {{if eq $i 0}}
-commands:
+{{$t.Heading.Render "commands:"}}
+
+{{end}}  {{format $cmd "\t" $t}}
+{{end}}
+{{- if not (cmd $) -}}
+{{- range $i, $topic := topics $.UI.Topics -}}
+{{if eq $i 0}}
+{{$t.Heading.Render "additional help topics:"}}
+
+{{end}}  {{topic $topic "\t" $t}}
+{{end}}
+{{- range $i, $p := plugins $.UI -}}
+{{if eq $i 0}}
+{{$t.Heading.Render "plugins:"}}
 
-{{end}}  {{format $cmd "\t"}}
+{{end}}  {{$t.Command.Render $p}}
 {{end}}
+{{- range $i, $a := aliases $.UI.Aliases -}}
+{{if eq $i 0}}
+{{$t.Heading.Render "aliases:"}}
+
+{{end}}  {{alias $a "\t" $t}}
+{{end}}
+{{- end -}}
 {{- $flags := flags .Flags -}}
 {{- range $i, $f := $flags -}}
 {{if eq $i 0}}
-options:
+{{$t.Heading.Render "options:"}}
 
-{{end}}  {{$f.Format "\t"}}
+{{end}}  {{flag $f "\t" $t}}
+{{end}}
+{{- range $i, $ex := .Examples -}}
+{{if eq $i 0}}
+{{$t.Heading.Render "examples:"}}
 {{end}}
+  {{$ex.Cmd}}
+{{with $ex.Desc}}{{indent . "      "}}
+{{end}}
+{{- end}}
 {{- if .Epilog}}
 {{.Epilog}}
-{{else if or .Desc (lt 0 (len .Cmds)) (lt 0 (len $flags))}}
+{{else if or .Desc (lt 0 (len .Cmds)) (lt 0 (len $flags)) (lt 0 (len .Examples))}}
 {{end -}}
 {{end}}`
 
 func FuncMap() template.FuncMap {
 	return template.FuncMap{
-		"usage":  Usage,
-		"cmd":    cmd,
-		"cmds":   cmds,
-		"format": format,
-		"flags":  flags,
+		"usage":   Usage,
+		"theme":   theme,
+		"cmd":     cmd,
+		"cmds":    cmds,
+		"format":  format,
+		"topics":  topics,
+		"topic":   formatTopic,
+		"plugins": (*CLI).plugins,
+		"aliases": aliases,
+		"alias":   formatAlias,
+		"flags":   flags,
+		"flag":    formatFlag,
+		"indent":  indent,
 	}
 }
 
@@ -103,15 +279,27 @@ func cmd(ctx *Context) *Command {
 }
 
 func cmds(cmds []*Command) []*Command {
-	list := make(CommandSlice, len(cmds))
-	copy(list, cmds)
+	var list CommandSlice
+	for _, c := range cmds {
+		if !c.Hidden {
+			list = append(list, c)
+		}
+	}
 	list.Sort()
 	return list
 }
 
-func format(cmd *Command, sep string) string {
+func theme(ctx *Context) *Theme {
+	return ctx.Theme(ctx.UI.Stdout)
+}
+
+func format(cmd *Command, sep string, th ...*Theme) string {
 	var b strings.Builder
-	b.WriteString(cmd.Name[0])
+	if len(th) > 0 && th[0] != nil {
+		b.WriteString(th[0].Command.Render(cmd.Name[0]))
+	} else {
+		b.WriteString(cmd.Name[0])
+	}
 	if cmd.Desc != "" {
 		b.WriteString(sep)
 		b.WriteString(strings.TrimSpace(strings.Split(cmd.Desc, "\n")[0]))
@@ -129,6 +317,23 @@ func flags(fs *FlagSet) []*Flag {
 	return flags
 }
 
+func formatFlag(f *Flag, sep string, th ...*Theme) string {
+	if len(th) > 0 && th[0] != nil {
+		return f.format(sep, th[0])
+	}
+	return f.Format(sep)
+}
+
+func indent(s, ind string) string {
+	lines := strings.Split(strings.TrimRight(Dedent(s), "\n"), "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = ind + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 func FormatUsage(ctx *Context) []string {
 	var cmd *Command
 	var u any
@@ -138,17 +343,9 @@ func FormatUsage(ctx *Context) []string {
 	} else {
 		u = ctx.UI.Usage
 	}
-	var usage []string
-	switch v := u.(type) {
-	case nil:
+	usage := usageList(u)
+	if usage == nil {
 		usage = []string{""}
-	case string:
-		usage = []string{v}
-	case []string:
-		usage = make([]string, len(v))
-		copy(usage, v)
-	default:
-		panic(fmt.Sprintf("unknown type '%T'", v))
 	}
 
 	for i, s := range usage {
@@ -1,7 +1,7 @@
 //
 // go.cli :: help_test.go
 //
-//   Copyright (c) 2014-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -210,6 +210,27 @@ var helpTests = []struct {
 
 		`),
 	},
+	{
+		cmds: []*cli.Command{
+			{
+				Name: []string{"cmd"},
+			},
+			{
+				Name:   []string{"hidden"},
+				Hidden: true,
+			},
+		},
+		out: cli.Dedent(`
+			usage: %[1]v
+
+			commands:
+
+			  cmd
+
+			%[2]v
+
+		`),
+	},
 }
 
 func TestHelp(t *testing.T) {
@@ -230,6 +251,106 @@ func TestHelp(t *testing.T) {
 	}
 }
 
+func TestHelpWrap(t *testing.T) {
+	t.Setenv("COLUMNS", "50")
+
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Desc = "a long description which exceeds the terminal width"
+	app.Stdout = &b
+	app.Flags.String("s, string", "", "a long usage of the flag which exceeds the terminal width\nsecond line")
+	app.Add(&cli.Command{
+		Name: []string{"cmd"},
+		Desc: "日本語の説明文は全角文字単位で折り返されます",
+	})
+	if err := app.Run([]string{"--help"}); err != nil {
+		t.Fatal(err)
+	}
+	out := cli.Dedent(`
+		usage: %[1]v
+
+		a long description which exceeds the terminal
+		width
+
+		commands:
+
+		  cmd    日本語の説明文は全角文字単位で折り返され
+		         ます
+
+		options:
+
+		  -h, --help               show help
+		  -s, --string <string>    a long usage of the
+		                           flag which exceeds the
+		                           terminal width
+		                           second line
+		  --version                show version
+		                           information
+
+	`)
+	if err := testOut(b.String(), fmt.Sprintf(out, app.Name)); err != nil {
+		t.Error(err)
+	}
+
+	// CLI.Env
+	t.Setenv("COLUMNS", "")
+	b.Reset()
+	app.Env = []string{"COLUMNS=50"}
+	if err := app.Run([]string{"--help"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(b.String(), fmt.Sprintf(out, app.Name)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHelpExamples(t *testing.T) {
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdout = &b
+	app.Examples = []cli.Example{
+		{
+			Cmd:  "app add foo",
+			Desc: "add foo",
+		},
+		{
+			Cmd: "app rm foo",
+			Desc: `
+				remove foo
+
+				  and more
+			`,
+		},
+		{
+			Cmd: "app ls",
+		},
+	}
+	if err := app.Run([]string{"--help"}); err != nil {
+		t.Fatal(err)
+	}
+	out := cli.Dedent(`
+		usage: %[1]v
+
+		%[2]v
+
+		examples:
+
+		  app add foo
+		      add foo
+
+		  app rm foo
+		      remove foo
+
+		        and more
+
+		  app ls
+
+	`)
+	if err := testOut(b.String(), fmt.Sprintf(out, app.Name, options)); err != nil {
+		t.Error(err)
+	}
+}
+
 var commandHelpTests = []struct {
 	alias  []string
 	usage  any
@@ -0,0 +1,114 @@
+//
+// go.cli :: pager.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+var DefaultPager = "less"
+
+func init() {
+	if runtime.GOOS == "windows" {
+		DefaultPager = "more"
+	}
+}
+
+func (ctx *Context) Page(fn func() error) error {
+	ui := ctx.UI
+	if _, ok := ui.Stdout.(*pager); ok || !isTerminal(ui.Stdout) {
+		return fn()
+	}
+	p := ui.pager()
+	if p == nil {
+		return fn()
+	}
+
+	stdout := ui.Stdout
+	ui.Stdout = p
+	defer func() { ui.Stdout = stdout }()
+	err := fn()
+	if perr := p.Close(); err == nil {
+		err = perr
+	}
+	return err
+}
+
+func (ui *CLI) pager() *pager {
+	s := ui.Pager
+	if s == "" {
+		s = ui.Getenv("PAGER")
+		if s == "" {
+			s = DefaultPager
+		}
+	}
+	args := strings.Fields(s)
+	if len(args) == 0 || args[0] == "cat" {
+		return nil
+	}
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return nil
+	}
+
+	cmd := exec.Command(path, args[1:]...)
+	cmd.Stdout = ui.Stdout
+	cmd.Stderr = ui.Stderr
+	cmd.Env = ui.Env
+	if ui.Getenv("LESS") == "" {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env[:len(cmd.Env):len(cmd.Env)], "LESS=FRX")
+	}
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return nil
+	}
+	if err := cmd.Start(); err != nil {
+		return nil
+	}
+	return &pager{
+		cmd: cmd,
+		w:   w,
+		out: ui.Stdout,
+	}
+}
+
+type pager struct {
+	cmd    *exec.Cmd
+	w      io.WriteCloser
+	out    io.Writer
+	closed bool
+}
+
+func (p *pager) Write(b []byte) (int, error) {
+	if !p.closed {
+		if _, err := p.w.Write(b); err != nil {
+			// pager exited early
+			p.closed = true
+		}
+	}
+	return len(b), nil
+}
+
+func (p *pager) Close() error {
+	p.closed = true
+	p.w.Close()
+	if err := p.cmd.Wait(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+//
+// go.cli :: pager_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hattya/go.cli"
+)
+
+func TestPagerProcess(t *testing.T) {
+	switch os.Getenv("GO_CLI_PAGER") {
+	case "cat":
+		fmt.Print("pager: ")
+		io.Copy(os.Stdout, os.Stdin)
+	case "head":
+		l, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		fmt.Print("pager: ", l)
+	default:
+		return
+	}
+	os.Exit(0)
+}
+
+func setupPager(t *testing.T, mode string) {
+	t.Helper()
+
+	isTerminal := *cli.IsTerminal
+	*cli.IsTerminal = func(io.Writer) bool { return true }
+	t.Cleanup(func() { *cli.IsTerminal = isTerminal })
+	t.Setenv("GO_CLI_PAGER", mode)
+	if strings.ContainsAny(os.Args[0], " \t") {
+		t.Skip("test binary path contains spaces")
+	}
+}
+
+func TestPage(t *testing.T) {
+	setupPager(t, "cat")
+
+	var b bytes.Buffer
+	setup := func(pager string) *cli.CLI {
+		app := cli.NewCLI()
+		app.Pager = pager
+		app.Stdout = &b
+		app.Action = func(ctx *cli.Context) error {
+			return ctx.Page(func() error {
+				ctx.UI.Println("output")
+				return nil
+			})
+		}
+		return app
+	}
+	pager := os.Args[0] + " -test.run=^TestPagerProcess$"
+
+	app := setup(pager)
+	if err := app.Run(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(b.String(), "pager: output\n"); err != nil {
+		t.Error(err)
+	}
+
+	// CLI.Env
+	b.Reset()
+	app = setup("")
+	app.Env = []string{"PAGER=" + pager, "GO_CLI_PAGER=head"}
+	if err := app.Run(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(b.String(), "pager: output\n"); err != nil {
+		t.Error(err)
+	}
+
+	// help
+	b.Reset()
+	app = setup(pager)
+	app.Color = cli.ColorNever
+	app.PageHelp = true
+	if err := app.Run([]string{"--help"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(b.String(), "pager: usage: ") {
+		t.Errorf("unexpected output: %q", b.String())
+	}
+
+	// fallback
+	for _, pager := range []string{"cat", "_"} {
+		b.Reset()
+		app = setup(pager)
+		if err := app.Run(nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := testOut(b.String(), "output\n"); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestPageEarlyExit(t *testing.T) {
+	setupPager(t, "head")
+
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Pager = os.Args[0] + " -test.run=^TestPagerProcess$"
+	app.Stdout = &b
+	app.Action = func(ctx *cli.Context) error {
+		return ctx.Page(func() error {
+			for i := range 100000 {
+				if _, err := ctx.UI.Println("line", i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err := app.Run(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(b.String(), "pager: line 0\n"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,96 @@
+//
+// go.cli :: plugin.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func (ui *CLI) pluginDirs() []string {
+	dirs := append([]string(nil), ui.PluginDirs...)
+	for _, dir := range filepath.SplitList(ui.Getenv("PATH")) {
+		// do not run plugins relative to the current directory
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+func (ui *CLI) lookPlugin(name string) (string, bool) {
+	if !ui.Plugins || name == "" || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	for _, dir := range ui.pluginDirs() {
+		if path, ok := findExecutable(dir, ui.Name+"-"+name); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+func (ui *CLI) plugins() []string {
+	if !ui.Plugins {
+		return nil
+	}
+	set := make(map[string]bool)
+	prefix := ui.Name + "-"
+	for _, dir := range ui.pluginDirs() {
+		list, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range list {
+			name, ok := pluginName(e.Name())
+			if !ok || !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+				continue
+			}
+			fi, err := os.Stat(filepath.Join(dir, e.Name()))
+			if err != nil || !isExecutable(fi) {
+				continue
+			}
+			name = name[len(prefix):]
+			if _, err := FindCommand(ui.Cmds, name); err != nil {
+				set[name] = true
+			}
+		}
+	}
+	list := make([]string, 0, len(set))
+	for n := range set {
+		list = append(list, n)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func pluginCommand(name, path string) *Command {
+	return &Command{
+		Name: []string{name},
+		Action: func(ctx *Context) error {
+			cmd := exec.CommandContext(ctx.Context(), path, ctx.Args...)
+			env := ctx.UI.Env
+			if env == nil {
+				env = os.Environ()
+			}
+			cmd.Env = append(env[:len(env):len(env)],
+				"CLI_NAME="+ctx.UI.Name,
+				"CLI_VERSION="+ctx.UI.Version,
+				"CLI_COMMAND="+name,
+			)
+			err := runProcess(ctx, cmd)
+			// the arguments are consumed by the plugin
+			ctx.Args = nil
+			return err
+		},
+	}
+}
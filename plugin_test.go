@@ -0,0 +1,178 @@
+//
+// go.cli :: plugin_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hattya/go.cli"
+)
+
+func TestPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires /bin/sh")
+	}
+
+	dir := t.TempDir()
+	for _, f := range []struct {
+		name string
+		perm os.FileMode
+	}{
+		{"app-foo", 0o755},
+		{"app-bar", 0o644},
+		{"app-echo", 0o755},
+		{"other-baz", 0o755},
+	} {
+		data := "#!/bin/sh\necho \"$CLI_NAME $CLI_VERSION $CLI_COMMAND $#: $*\"\nread s && echo \"$s\"\nexit 4\n"
+		if err := os.WriteFile(filepath.Join(dir, f.name), []byte(data), f.perm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app-ok"), []byte("#!/bin/sh\necho ok \"$@\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Version = "1.0"
+	app.Stdin = strings.NewReader("stdin\n")
+	app.Stdout = &stdout
+	app.Stderr = &stderr
+	app.Env = []string{"PATH="}
+	app.Plugins = true
+	app.PluginDirs = []string{dir}
+	app.Add(&cli.Command{
+		Name:  []string{"echo"},
+		Flags: cli.NewFlagSet(),
+		Action: func(ctx *cli.Context) error {
+			ctx.UI.Println("echo")
+			return nil
+		},
+	})
+	err := app.Run([]string{"foo", "-x", "bar baz"})
+	if g, e := err, cli.ExitStatus(4); g != e {
+		t.Errorf("expected %#v, got %#v", e, g)
+	}
+	if err := testOut(stdout.String(), "app 1.0 foo 2: -x bar baz\nstdin\n"); err != nil {
+		t.Error(err)
+	}
+	if g := stderr.String(); g != "" {
+		t.Errorf("unexpected output: %q", g)
+	}
+
+	// the arguments are not run by Chain
+	stdout.Reset()
+	app.Action = cli.Chain
+	if err := app.Run([]string{"ok", "echo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(stdout.String(), "ok echo\n"); err != nil {
+		t.Error(err)
+	}
+	app.Action = cli.Subcommand
+
+	// built-in command
+	stdout.Reset()
+	if err := app.Run([]string{"echo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(stdout.String(), "echo\n"); err != nil {
+		t.Error(err)
+	}
+
+	for _, args := range [][]string{
+		{"bar"},
+		{"baz"},
+		{"../app-foo"},
+	} {
+		if err := app.Run(args); !errors.As(err, new(cli.CommandError)) {
+			t.Errorf("%v: expected CommandError, got %#v", args, err)
+		}
+	}
+
+	app.Plugins = false
+	if err := app.Run([]string{"foo"}); !errors.As(err, new(cli.CommandError)) {
+		t.Errorf("expected CommandError, got %#v", err)
+	}
+}
+
+func TestPluginPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires /bin/sh")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app-foo"), []byte("#!/bin/sh\necho foo\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Plugins = true
+	app.Add(&cli.Command{
+		Name:  []string{"echo"},
+		Flags: cli.NewFlagSet(),
+	})
+	// empty and relative entries are skipped
+	for _, path := range []string{"", ".", string(filepath.ListSeparator) + "."} {
+		app.Env = []string{"PATH=" + path}
+		if err := app.Run([]string{"foo"}); !errors.As(err, new(cli.CommandError)) {
+			t.Errorf("PATH=%q: expected CommandError, got %#v", path, err)
+		}
+	}
+
+	b.Reset()
+	app.Env = []string{"PATH=" + dir}
+	if err := app.Run([]string{"foo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(b.String(), "foo\n"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPluginHelp(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires /bin/sh")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app-foo"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Env = []string{"PATH="}
+	app.Plugins = true
+	app.PluginDirs = []string{dir}
+	if err := app.Run([]string{"-h"}); err != nil {
+		t.Fatal(err)
+	}
+	e := cli.Dedent(`
+		plugins:
+
+		  foo
+
+	`)
+	if g := b.String(); !strings.Contains(g, e) {
+		t.Errorf("expected %q in %q", e, g)
+	}
+}
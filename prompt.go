@@ -0,0 +1,576 @@
+//
+// go.cli :: prompt.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+var (
+	ErrNotTerminal = errors.New("cli: stdin is not a terminal")
+	ErrAttempts    = errors.New("cli: too many attempts")
+)
+
+type InputPolicy int
+
+const (
+	InputRead InputPolicy = iota
+	InputDefault
+	InputError
+)
+
+type PromptOption func(*promptOptions)
+
+func PromptDefault(def string) PromptOption {
+	return func(o *promptOptions) {
+		o.def = def
+		o.hasDef = true
+	}
+}
+
+func PromptValidator(fn func(string) error) PromptOption {
+	return func(o *promptOptions) {
+		o.validate = fn
+	}
+}
+
+func PromptAttempts(n int) PromptOption {
+	return func(o *promptOptions) {
+		o.attempts = n
+	}
+}
+
+func PasswordConfirm(prompt string) PromptOption {
+	return func(o *promptOptions) {
+		o.confirm = prompt
+	}
+}
+
+type promptOptions struct {
+	def      string
+	hasDef   bool
+	validate func(string) error
+	attempts int
+	confirm  string
+	history  string
+	complete func(string) []string
+}
+
+func newPromptOptions(opts []PromptOption) *promptOptions {
+	o := new(promptOptions)
+	for _, fn := range opts {
+		fn(o)
+	}
+	return o
+}
+
+func (o *promptOptions) input(ui *CLI, prompt string, read func(string) (string, error)) (string, error) {
+	for n := 1; ; n++ {
+		s, err := read(prompt)
+		if err != nil {
+			return s, err
+		}
+		if s == "" && o.hasDef {
+			s = o.def
+		}
+		if o.validate != nil {
+			err = o.validate(s)
+		}
+		if err == nil && o.confirm != "" {
+			var again string
+			if again, err = read(o.confirm); err != nil {
+				return "", err
+			} else if again != s {
+				err = errors.New("inputs do not match")
+			}
+		}
+		if err == nil {
+			return s, nil
+		}
+		ui.Println(err)
+		if 0 < o.attempts && o.attempts <= n {
+			return "", ErrAttempts
+		}
+	}
+}
+
+func defaultPrompt(prompt, def string) string {
+	s := strings.TrimRight(prompt, " ")
+	sep := prompt[len(s):]
+	if strings.HasSuffix(s, ":") {
+		s = s[:len(s)-1]
+		sep = ":" + sep
+	}
+	return fmt.Sprintf("%v [%v]%v", s, def, sep)
+}
+
+func (ui *CLI) Confirm(prompt string, def bool) (bool, error) {
+	if ui.assumeYes() {
+		return true, nil
+	}
+	if !ui.interactive() {
+		switch ui.NonInteractive {
+		case InputDefault:
+			return def, nil
+		case InputError:
+			return false, ErrNotTerminal
+		}
+	}
+
+	hint := " [y/N] "
+	if def {
+		hint = " [Y/n] "
+	}
+	for {
+		ui.Print(prompt + hint)
+		s, err := ui.readLine(ui.Context())
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		ui.Println("please answer yes or no")
+	}
+}
+
+func (fs *FlagSet) YesFlag(name, usage string) *Flag {
+	if name == "" {
+		name = "yes"
+	}
+	if usage == "" {
+		usage = "assume yes to all questions"
+	}
+	f := fs.Bool(name, false, usage)
+	f.role = roleYes
+	return f
+}
+
+func (ui *CLI) assumeYes() bool {
+	if ui.AssumeYes {
+		return true
+	}
+	if ui.Flags != nil {
+		if f := ui.Flags.lookupRole(roleYes); f != nil {
+			v, _ := f.Value.Get().(bool)
+			return v
+		}
+	}
+	return false
+}
+
+func (ui *CLI) interactive() bool {
+	f, ok := ui.Stdin.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+func (ui *CLI) Select(prompt string, choices map[string]any) (any, error) {
+	keys := choiceKeys(choices)
+	list, err := ui.choose(prompt, keys, false)
+	if err != nil {
+		return nil, err
+	}
+	return choices[keys[list[0]]], nil
+}
+
+func (ui *CLI) MultiSelect(prompt string, choices map[string]any) ([]any, error) {
+	keys := choiceKeys(choices)
+	list, err := ui.choose(prompt, keys, true)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, len(list))
+	for i, j := range list {
+		values[i] = choices[keys[j]]
+	}
+	return values, nil
+}
+
+func choiceKeys(choices map[string]any) []string {
+	keys := make([]string, 0, len(choices))
+	for k := range choices {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (ui *CLI) choose(prompt string, keys []string, multi bool) ([]int, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("cli: no choices")
+	}
+	if ui.interactive() {
+		return ui.menu(prompt, keys, multi)
+	}
+	if ui.NonInteractive == InputError {
+		return nil, ErrNotTerminal
+	}
+
+	ui.Println(prompt)
+	for i, k := range keys {
+		ui.Printf("  %v) %v\n", i+1, k)
+	}
+	for {
+		ui.Print("> ")
+		s, err := ui.readLine(ui.Context())
+		if err != nil {
+			return nil, err
+		}
+		list, err := parseChoices(s, keys, multi)
+		if err == nil {
+			return list, nil
+		}
+		ui.Println(err)
+	}
+}
+
+func parseChoices(s string, keys []string, multi bool) ([]int, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	switch {
+	case len(fields) == 0 && !multi:
+		return nil, errors.New("please choose one")
+	case len(fields) > 1 && !multi:
+		return nil, errors.New("please choose only one")
+	}
+
+	seen := make(map[int]bool)
+	for _, f := range fields {
+		lo, hi, err := parseChoice(f, keys)
+		if err != nil {
+			return nil, err
+		}
+		for i := lo; i <= hi; i++ {
+			seen[i] = true
+		}
+	}
+	list := make([]int, 0, len(seen))
+	for i := range keys {
+		if seen[i] {
+			list = append(list, i)
+		}
+	}
+	return list, nil
+}
+
+func parseChoice(s string, keys []string) (lo, hi int, err error) {
+	// number or range of numbers
+	if a, b, ok := strings.Cut(s, "-"); ok {
+		lo, err1 := strconv.Atoi(a)
+		hi, err2 := strconv.Atoi(b)
+		if err1 == nil && err2 == nil && 0 < lo && lo <= hi && hi <= len(keys) {
+			return lo - 1, hi - 1, nil
+		}
+	} else if i, err := strconv.Atoi(s); err == nil {
+		if 0 < i && i <= len(keys) {
+			return i - 1, i - 1, nil
+		}
+		return 0, 0, fmt.Errorf("invalid choice: %v", s)
+	}
+	// exact or prefix match
+	var m []int
+	for i, k := range keys {
+		switch {
+		case k == s:
+			return i, i, nil
+		case strings.HasPrefix(k, s):
+			m = append(m, i)
+		}
+	}
+	switch len(m) {
+	case 0:
+		return 0, 0, fmt.Errorf("invalid choice: %v", s)
+	case 1:
+		return m[0], m[0], nil
+	}
+	list := make([]string, len(m))
+	for i, j := range m {
+		list[i] = keys[j]
+	}
+	return 0, 0, fmt.Errorf("ambiguous choice: %v (%v)", s, strings.Join(list, ", "))
+}
+
+func (ui *CLI) menu(prompt string, keys []string, multi bool) ([]int, error) {
+	if f, ok := ui.Stdin.(*os.File); ok {
+		st, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return nil, err
+		}
+		defer term.Restore(int(f.Fd()), st)
+	}
+
+	cur := 0
+	checked := make([]bool, len(keys))
+	draw := func(redraw bool) {
+		var b strings.Builder
+		if redraw {
+			fmt.Fprintf(&b, "\x1b[%vA", len(keys))
+		}
+		for i, k := range keys {
+			b.WriteString("\r\x1b[K")
+			if i == cur {
+				b.WriteString("> ")
+			} else {
+				b.WriteString("  ")
+			}
+			if multi {
+				if checked[i] {
+					b.WriteString("[x] ")
+				} else {
+					b.WriteString("[ ] ")
+				}
+			}
+			b.WriteString(k)
+			b.WriteString("\r\n")
+		}
+		ui.Print(b.String())
+	}
+	ui.Print(prompt, "\r\n")
+	draw(false)
+
+	var buf []byte
+	for {
+		b, err := ui.read(ui.Context())
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, b...)
+		for len(buf) > 0 {
+			var k rune
+			switch {
+			case strings.HasPrefix(string(buf), "\x1b[A"), strings.HasPrefix(string(buf), "\x1bOA"):
+				k = 'k'
+				buf = buf[3:]
+			case strings.HasPrefix(string(buf), "\x1b[B"), strings.HasPrefix(string(buf), "\x1bOB"):
+				k = 'j'
+				buf = buf[3:]
+			case buf[0] == 0x1b && len(buf) < 3:
+				// incomplete escape sequence
+				k = -1
+			default:
+				k = rune(buf[0])
+				buf = buf[1:]
+			}
+			if k == -1 {
+				break
+			}
+
+			switch k {
+			case 'k', 0x10: // Ctrl-P
+				cur = (cur + len(keys) - 1) % len(keys)
+			case 'j', 0x0e: // Ctrl-N
+				cur = (cur + 1) % len(keys)
+			case ' ':
+				if multi {
+					checked[cur] = !checked[cur]
+				}
+			case '\r', '\n':
+				var list []int
+				if multi {
+					list = []int{}
+					for i := range keys {
+						if checked[i] {
+							list = append(list, i)
+						}
+					}
+				} else {
+					list = []int{cur}
+				}
+				return list, nil
+			case 0x03: // Ctrl-C
+				return nil, Interrupt{}
+			case 0x04: // Ctrl-D
+				return nil, io.EOF
+			default:
+				if '1' <= k && k <= '9' && int(k-'1') < len(keys) {
+					cur = int(k - '1')
+				} else {
+					continue
+				}
+			}
+			draw(true)
+		}
+	}
+}
+
+func (ui *CLI) readLine(ctx context.Context) (string, error) {
+	return ui.reader().readLine(ctx, ui.ctx)
+}
+
+func (ui *CLI) read(ctx context.Context) ([]byte, error) {
+	return ui.reader().read(ctx, ui.ctx)
+}
+
+func (ui *CLI) reader() *lineReader {
+	in := ui.input()
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if in.lr == nil || in.lr.r != ui.Stdin {
+		in.lr = &lineReader{r: ui.Stdin}
+	}
+	return in.lr
+}
+
+// input is the state of Stdin which is shared by runs of a CLI.
+type input struct {
+	mu sync.Mutex
+	lr *lineReader
+	ed *editor
+}
+
+func (ui *CLI) input() *input {
+	if ui.in == nil {
+		ui.in = new(input)
+	}
+	return ui.in
+}
+
+func (ui *CLI) readPassword(ctx context.Context, fd int) (string, error) {
+	type result struct {
+		b   []byte
+		err error
+	}
+	// ReadPassword restores the terminal state only when it returns
+	st, err := term.GetState(fd)
+	if err != nil {
+		return "", err
+	}
+	ch := make(chan result, 1)
+	go func() {
+		b, err := term.ReadPassword(fd)
+		ch <- result{b, err}
+	}()
+	select {
+	case r := <-ch:
+		return string(r.b), r.err
+	case <-ctx.Done():
+		term.Restore(fd, st)
+		return "", contextError(ctx, ui.ctx)
+	}
+}
+
+// lineReader is a buffered reader which can be cancelled by a context.
+// A Read which is in progress when the context is cancelled is not
+// abandoned; its result is used by the next call.
+//
+// It reads a byte at a time so that it never reads ahead of what was asked
+// for, and the rest of the input can still be read from the underlying
+// reader directly.
+type lineReader struct {
+	mu      sync.Mutex
+	r       io.Reader
+	buf     []byte
+	err     error
+	pending chan struct{}
+}
+
+func (lr *lineReader) readLine(ctx, ui context.Context) (string, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	for {
+		if i := bytes.IndexByte(lr.buf, '\n'); i != -1 {
+			line := lr.buf[:i]
+			lr.buf = lr.buf[i+1:]
+			return string(bytes.TrimSuffix(line, []byte{'\r'})), nil
+		}
+		if lr.err != nil {
+			line := string(lr.buf)
+			err := lr.err
+			if err == io.EOF && len(line) > 0 {
+				err = nil
+			}
+			lr.buf = nil
+			lr.err = nil
+			return line, err
+		}
+		if err := lr.fill(ctx, ui); err != nil {
+			return "", err
+		}
+	}
+}
+
+func (lr *lineReader) read(ctx, ui context.Context) ([]byte, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	for {
+		switch {
+		case len(lr.buf) > 0:
+			b := lr.buf
+			lr.buf = nil
+			return b, nil
+		case lr.err != nil:
+			err := lr.err
+			lr.err = nil
+			return nil, err
+		}
+		if err := lr.fill(ctx, ui); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (lr *lineReader) unread(b []byte) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	if len(b) > 0 {
+		lr.buf = append(append([]byte(nil), b...), lr.buf...)
+	}
+}
+
+// fill reads a byte into the buffer. It must be called with lr.mu held, and
+// releases it while waiting for the byte.
+func (lr *lineReader) fill(ctx, ui context.Context) error {
+	if lr.pending == nil {
+		done := make(chan struct{})
+		go func() {
+			var b [1]byte
+			n, err := lr.r.Read(b[:])
+			lr.mu.Lock()
+			lr.buf = append(lr.buf, b[:n]...)
+			if err != nil {
+				lr.err = err
+			}
+			lr.pending = nil
+			lr.mu.Unlock()
+			close(done)
+		}()
+		lr.pending = done
+	}
+	done := lr.pending
+	lr.mu.Unlock()
+	defer lr.mu.Lock()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return contextError(ctx, ui)
+	}
+}
+
+func contextError(ctx, ui context.Context) error {
+	if ui.Err() != nil {
+		return Interrupt{}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,590 @@
+//
+// go.cli :: prompt_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hattya/go.cli"
+)
+
+var confirmTests = []struct {
+	in  string
+	def bool
+	ok  bool
+	out string
+}{
+	{
+		in:  "y\n",
+		out: "? [y/N] ",
+		ok:  true,
+	},
+	{
+		in:  "YES\n",
+		out: "? [y/N] ",
+		ok:  true,
+	},
+	{
+		in:  "n\n",
+		def: true,
+		out: "? [Y/n] ",
+		ok:  false,
+	},
+	{
+		in:  " No \n",
+		def: true,
+		out: "? [Y/n] ",
+		ok:  false,
+	},
+	{
+		in:  "\n",
+		def: true,
+		out: "? [Y/n] ",
+		ok:  true,
+	},
+	{
+		in:  "\n",
+		out: "? [y/N] ",
+		ok:  false,
+	},
+	{
+		in:  "_\ny\n",
+		out: "? [y/N] please answer yes or no\n? [y/N] ",
+		ok:  true,
+	},
+}
+
+func TestConfirm(t *testing.T) {
+	var stdin, stdout bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdin = &stdin
+	app.Stdout = &stdout
+	for _, tt := range confirmTests {
+		stdin.Reset()
+		stdout.Reset()
+
+		stdin.WriteString(tt.in)
+		ok, err := app.Confirm("?", tt.def)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g, e := ok, tt.ok; g != e {
+			t.Errorf("Confirm(%q) = %v, expected %v", tt.in, g, e)
+		}
+		if err := testOut(stdout.String(), tt.out); err != nil {
+			t.Error(err)
+		}
+	}
+
+	stdin.Reset()
+	if _, err := app.Confirm("?", true); err != io.EOF {
+		t.Errorf("expected EOF, got %#v", err)
+	}
+}
+
+func TestConfirmAssumeYes(t *testing.T) {
+	var stdout bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdin = new(bytes.Buffer)
+	app.Stdout = &stdout
+	app.AssumeYes = true
+	if ok, err := app.Confirm("?", false); err != nil || !ok {
+		t.Errorf("Confirm() = %v, %v, expected true, nil", ok, err)
+	}
+
+	app = cli.NewCLI()
+	app.Stdin = new(bytes.Buffer)
+	app.Stdout = &stdout
+	app.Flags.YesFlag("y, yes", "")
+	app.Flags.Set("y", "true")
+	if ok, err := app.Confirm("?", false); err != nil || !ok {
+		t.Errorf("Confirm() = %v, %v, expected true, nil", ok, err)
+	}
+
+	if g := stdout.String(); g != "" {
+		t.Errorf("unexpected output: %q", g)
+	}
+
+	// a flag which is not registered by YesFlag
+	app = cli.NewCLI()
+	app.Stdin = strings.NewReader("n\n")
+	app.Stdout = io.Discard
+	app.Flags.Bool("yes", false, "")
+	app.Flags.Set("yes", "true")
+	if ok, err := app.Confirm("?", false); err != nil || ok {
+		t.Errorf("Confirm() = %v, %v, expected false, nil", ok, err)
+	}
+}
+
+func TestConfirmNonInteractive(t *testing.T) {
+	app := cli.NewCLI()
+	app.Stdin = new(bytes.Buffer)
+	app.Stdout = io.Discard
+	app.NonInteractive = cli.InputDefault
+	for _, def := range []bool{false, true} {
+		if ok, err := app.Confirm("?", def); err != nil || ok != def {
+			t.Errorf("Confirm() = %v, %v, expected %v, nil", ok, err, def)
+		}
+	}
+
+	app.NonInteractive = cli.InputError
+	if _, err := app.Confirm("?", true); err != cli.ErrNotTerminal {
+		t.Errorf("expected ErrNotTerminal, got %#v", err)
+	}
+}
+
+func TestConfirmInterrupt(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	app := cli.NewCLI()
+	app.Stdin = r
+	app.Stdout = io.Discard
+	app.Interrupt()
+	if _, err := app.Confirm("?", true); err != (cli.Interrupt{}) {
+		t.Errorf("expected Interrupt, got %#v", err)
+	}
+}
+
+var choices = map[string]any{
+	"foo":    1,
+	"bar":    2,
+	"baz":    3,
+	"foobar": 4,
+}
+
+var selectTests = []struct {
+	in    string
+	value any
+	out   string
+}{
+	{
+		in:    "1\n",
+		value: 2,
+	},
+	{
+		in:    "foo\n",
+		value: 1,
+	},
+	{
+		in:    "foob\n",
+		value: 4,
+	},
+	{
+		in:    "5\n\nb\nbar baz\nbaz\n",
+		value: 3,
+		out: "invalid choice: 5\n> " +
+			"please choose one\n> " +
+			"ambiguous choice: b (bar, baz)\n> " +
+			"please choose only one\n> ",
+	},
+}
+
+func TestSelect(t *testing.T) {
+	var stdin, stdout bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdin = &stdin
+	app.Stdout = &stdout
+	for _, tt := range selectTests {
+		stdin.Reset()
+		stdout.Reset()
+
+		stdin.WriteString(tt.in)
+		v, err := app.Select("?", choices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g, e := v, tt.value; g != e {
+			t.Errorf("Select(%q) = %v, expected %v", tt.in, g, e)
+		}
+		out := "?\n  1) bar\n  2) baz\n  3) foo\n  4) foobar\n> " + tt.out
+		if err := testOut(stdout.String(), out); err != nil {
+			t.Error(err)
+		}
+	}
+
+	stdin.Reset()
+	if _, err := app.Select("?", choices); err != io.EOF {
+		t.Errorf("expected EOF, got %#v", err)
+	}
+	if _, err := app.Select("?", nil); err == nil {
+		t.Error("expected error")
+	}
+
+	app.NonInteractive = cli.InputError
+	if _, err := app.Select("?", choices); err != cli.ErrNotTerminal {
+		t.Errorf("expected ErrNotTerminal, got %#v", err)
+	}
+}
+
+var multiSelectTests = []struct {
+	in     string
+	values []any
+}{
+	{
+		in:     "\n",
+		values: []any{},
+	},
+	{
+		in:     "3, 1\n",
+		values: []any{2, 1},
+	},
+	{
+		in:     "2-4 foo\n",
+		values: []any{3, 1, 4},
+	},
+	{
+		in:     "4-2\nbar,foob\n",
+		values: []any{2, 4},
+	},
+}
+
+func TestMultiSelect(t *testing.T) {
+	var stdin bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdin = &stdin
+	app.Stdout = io.Discard
+	for _, tt := range multiSelectTests {
+		stdin.Reset()
+
+		stdin.WriteString(tt.in)
+		values, err := app.MultiSelect("?", choices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g, e := fmt.Sprint(values), fmt.Sprint(tt.values); g != e {
+			t.Errorf("MultiSelect(%q) = %v, expected %v", tt.in, g, e)
+		}
+	}
+
+	stdin.Reset()
+	if _, err := app.MultiSelect("?", choices); err != io.EOF {
+		t.Errorf("expected EOF, got %#v", err)
+	}
+}
+
+func TestSelectInterrupt(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	app := cli.NewCLI()
+	app.Stdin = r
+	app.Stdout = io.Discard
+	app.Interrupt()
+	if _, err := app.Select("?", choices); err != (cli.Interrupt{}) {
+		t.Errorf("expected Interrupt, got %#v", err)
+	}
+}
+
+var menuTests = []struct {
+	in    string
+	multi bool
+	list  []int
+}{
+	{
+		in:   "\r",
+		list: []int{0},
+	},
+	{
+		in:   "\x1b[B\x1b[B\x1b[A\r",
+		list: []int{1},
+	},
+	{
+		in:   "\x1b[Ak\r",
+		list: []int{1},
+	},
+	{
+		in:   "3\r",
+		list: []int{2},
+	},
+	{
+		in:    "\r",
+		multi: true,
+		list:  []int{},
+	},
+	{
+		in:    " j\x0e  \x10 \r",
+		multi: true,
+		list:  []int{0, 1},
+	},
+}
+
+func TestMenu(t *testing.T) {
+	var stdin bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdin = &stdin
+	app.Stdout = io.Discard
+	keys := []string{"bar", "baz", "foo"}
+	for _, tt := range menuTests {
+		stdin.Reset()
+
+		stdin.WriteString(tt.in)
+		list, err := cli.Menu(app, "?", keys, tt.multi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g, e := fmt.Sprint(list), fmt.Sprint(tt.list); g != e {
+			t.Errorf("menu(%q) = %v, expected %v", tt.in, g, e)
+		}
+	}
+
+	// redraw
+	var stdout bytes.Buffer
+	app.Stdout = &stdout
+	stdin.Reset()
+	stdin.WriteString("3x\r")
+	if _, err := cli.Menu(app, "?", keys, false); err != nil {
+		t.Fatal(err)
+	}
+	out := "?\r\n" +
+		"\r\x1b[K> bar\r\n\r\x1b[K  baz\r\n\r\x1b[K  foo\r\n" +
+		"\x1b[3A" +
+		"\r\x1b[K  bar\r\n\r\x1b[K  baz\r\n\r\x1b[K> foo\r\n"
+	if err := testOut(stdout.String(), out); err != nil {
+		t.Error(err)
+	}
+	app.Stdout = io.Discard
+
+	for _, tt := range []struct {
+		in  string
+		err error
+	}{
+		{"\x03", cli.Interrupt{}},
+		{"\x04", io.EOF},
+		{"", io.EOF},
+	} {
+		stdin.Reset()
+
+		stdin.WriteString(tt.in)
+		if _, err := cli.Menu(app, "?", keys, false); err != tt.err {
+			t.Errorf("expected %#v, got %#v", tt.err, err)
+		}
+	}
+}
+
+var promptOptionTests = []struct {
+	in   string
+	opts []cli.PromptOption
+	s    string
+	out  string
+}{
+	{
+		in:   "\n",
+		opts: []cli.PromptOption{cli.PromptDefault("foo")},
+		s:    "foo",
+		out:  "name [foo]: ",
+	},
+	{
+		in:   "bar\n",
+		opts: []cli.PromptOption{cli.PromptDefault("foo")},
+		s:    "bar",
+		out:  "name [foo]: ",
+	},
+	{
+		in:   "\nfoo\n",
+		opts: []cli.PromptOption{cli.PromptValidator(required)},
+		s:    "foo",
+		out:  "name: required\nname: ",
+	},
+	{
+		in: "\n",
+		opts: []cli.PromptOption{
+			cli.PromptDefault("foo"),
+			cli.PromptValidator(required),
+		},
+		s:   "foo",
+		out: "name [foo]: ",
+	},
+}
+
+func required(s string) error {
+	if s == "" {
+		return errors.New("required")
+	}
+	return nil
+}
+
+func TestPromptOptions(t *testing.T) {
+	var stdin, stdout bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdin = &stdin
+	app.Stdout = &stdout
+	for _, tt := range promptOptionTests {
+		stdin.Reset()
+		stdout.Reset()
+
+		stdin.WriteString(tt.in)
+		s, err := app.Prompt("name: ", tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g, e := s, tt.s; g != e {
+			t.Errorf("Prompt(%q) = %q, expected %q", tt.in, g, e)
+		}
+		if err := testOut(stdout.String(), tt.out); err != nil {
+			t.Error(err)
+		}
+	}
+
+	stdin.Reset()
+	stdout.Reset()
+	stdin.WriteString("\n\n\n")
+	if _, err := app.Prompt("name: ", cli.PromptValidator(required), cli.PromptAttempts(2)); err != cli.ErrAttempts {
+		t.Errorf("expected ErrAttempts, got %#v", err)
+	}
+	if err := testOut(stdout.String(), "name: required\nname: required\n"); err != nil {
+		t.Error(err)
+	}
+
+	stdin.Reset()
+	stdin.WriteString("\n")
+	if _, err := app.Prompt("name: ", cli.PromptValidator(required)); err != io.EOF {
+		t.Errorf("expected EOF, got %#v", err)
+	}
+}
+
+func TestPasswordConfirm(t *testing.T) {
+	var stdin, stdout bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdin = &stdin
+	app.Stdout = &stdout
+
+	stdin.WriteString("foo\nbar\nfoo\nfoo\n")
+	s, err := app.Password("password: ", cli.PasswordConfirm("again: "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := s, "foo"; g != e {
+		t.Errorf("Password() = %q, expected %q", g, e)
+	}
+	out := "password: \nagain: \ninputs do not match\npassword: \nagain: \n"
+	if err := testOut(stdout.String(), out); err != nil {
+		t.Error(err)
+	}
+
+	stdin.Reset()
+	stdout.Reset()
+	stdin.WriteString("foo\nbar\n")
+	if _, err := app.Password("password: ", cli.PasswordConfirm("again: "), cli.PromptAttempts(1)); err != cli.ErrAttempts {
+		t.Errorf("expected ErrAttempts, got %#v", err)
+	}
+
+	stdin.Reset()
+	stdin.WriteString("foo\n")
+	if _, err := app.Password("password: ", cli.PasswordConfirm("again: ")); err != io.EOF {
+		t.Errorf("expected EOF, got %#v", err)
+	}
+}
+
+func TestPromptContext(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	app := cli.NewCLI()
+	app.Stdin = r
+	app.Stdout = io.Discard
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := app.PromptContext(ctx, "?"); err != context.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, got %#v", err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := app.PasswordContext(ctx, "?"); err != context.Canceled {
+		t.Errorf("expected Canceled, got %#v", err)
+	}
+	// input which was read ahead is not lost
+	go w.Write([]byte("foo\nbar\n"))
+	for _, e := range []string{"foo", "bar"} {
+		s, err := app.Prompt("?")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g := s; g != e {
+			t.Errorf("Prompt() = %q, expected %q", g, e)
+		}
+	}
+
+	app.Interrupt()
+	if _, err := app.Prompt("?"); err != (cli.Interrupt{}) {
+		t.Errorf("expected Interrupt, got %#v", err)
+	}
+}
+
+func TestPromptStdin(t *testing.T) {
+	app := cli.NewCLI()
+	app.Stdin = strings.NewReader("foo\nbar\nbaz\n")
+	app.Stdout = io.Discard
+	app.Stderr = io.Discard
+	app.Action = func(ctx *cli.Context) error {
+		s, err := ctx.UI.Prompt("?")
+		if err != nil {
+			return err
+		}
+		if g, e := s, "foo"; g != e {
+			t.Errorf("Prompt() = %q, expected %q", g, e)
+		}
+		// the rest of the input is not read ahead
+		b, err := io.ReadAll(ctx.UI.Stdin)
+		if err != nil {
+			return err
+		}
+		if g, e := string(b), "bar\nbaz\n"; g != e {
+			t.Errorf("expected %q, got %q", e, g)
+		}
+		return nil
+	}
+	if err := app.Run(nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPromptConcurrent(t *testing.T) {
+	var b strings.Builder
+	for i := range 16 {
+		fmt.Fprintln(&b, i)
+	}
+	app := cli.NewCLI()
+	app.Stdin = strings.NewReader(b.String())
+	app.Stdout = io.Discard
+
+	var wg sync.WaitGroup
+	lines := make([]string, 16)
+	for i := range lines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			s, err := app.Prompt("?")
+			if err != nil {
+				t.Error(err)
+			}
+			lines[i] = s
+		}()
+	}
+	wg.Wait()
+	sort.Slice(lines, func(i, j int) bool {
+		x, _ := strconv.Atoi(lines[i])
+		y, _ := strconv.Atoi(lines[j])
+		return x < y
+	})
+	for i, s := range lines {
+		if g, e := s, strconv.Itoa(i); g != e {
+			t.Errorf("expected %q, got %q", e, g)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+//
+// go.cli :: response.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const maxResponseDepth = 8
+
+var ErrResponseDepth = errors.New("cli: response files are nested too deeply")
+
+type ResponseFileError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e ResponseFileError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%v:%v: %v", e.File, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%v: %v", e.File, e.Err)
+}
+
+func (e ResponseFileError) Unwrap() error { return e.Err }
+
+// expandArgs replaces @file arguments with the arguments which are read from
+// the files. @@ is replaced with a literal @, and the arguments after "--"
+// are not expanded.
+func (ui *CLI) expandArgs(args []string) ([]string, error) {
+	list, _, err := ui.expand(nil, args, "", 0, 0)
+	return list, err
+}
+
+func (ui *CLI) expand(list, args []string, file string, line, depth int) ([]string, bool, error) {
+	for i, a := range args {
+		switch {
+		case a == "--":
+			return append(list, args[i:]...), true, nil
+		case strings.HasPrefix(a, "@@"):
+			list = append(list, a[1:])
+		case len(a) > 1 && a[0] == '@':
+			if depth == maxResponseDepth {
+				return nil, false, ResponseFileError{
+					File: file,
+					Line: line,
+					Err:  ErrResponseDepth,
+				}
+			}
+			var done bool
+			var err error
+			if list, done, err = ui.readResponseFile(list, a[1:], depth+1); err != nil {
+				return nil, false, err
+			} else if done {
+				return append(list, args[i+1:]...), true, nil
+			}
+		default:
+			list = append(list, a)
+		}
+	}
+	return list, false, nil
+}
+
+func (ui *CLI) readResponseFile(list []string, name string, depth int) ([]string, bool, error) {
+	path := name
+	if ui.Dir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(ui.Dir, path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		var pe *os.PathError
+		if errors.As(err, &pe) {
+			err = pe.Err
+		}
+		return nil, false, ResponseFileError{
+			File: name,
+			Err:  err,
+		}
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 1<<20)
+	n := 0
+	for s.Scan() {
+		n++
+		args, err := Split(s.Text())
+		if err != nil {
+			return nil, false, ResponseFileError{
+				File: name,
+				Line: n,
+				Err:  err,
+			}
+		}
+		var done bool
+		if list, done, err = ui.expand(list, args, name, n, depth); err != nil || done {
+			return list, done, err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, false, ResponseFileError{
+			File: name,
+			Err:  err,
+		}
+	}
+	return list, false, nil
+}
@@ -0,0 +1,110 @@
+//
+// go.cli :: response_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hattya/go.cli"
+)
+
+func TestResponseFile(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"args.txt":   "-n 1\n\n'foo bar' \"baz\"\n@nested.txt @@qux\n",
+		"nested.txt": "nested -- @args.txt\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Dir = dir
+	app.ResponseFiles = true
+	flags := cli.NewFlagSet()
+	flags.Int("n", 0, "")
+	app.Add(&cli.Command{
+		Name:  []string{"echo"},
+		Flags: flags,
+		Action: func(ctx *cli.Context) error {
+			ctx.UI.Printf("%v %q\n", ctx.Int("n"), ctx.Args)
+			return nil
+		},
+	})
+	for _, tt := range []struct {
+		args []string
+		out  string
+	}{
+		{[]string{"echo", "@args.txt", "x"}, `1 ["foo bar" "baz" "nested" "--" "@args.txt" "@@qux" "x"]`},
+		{[]string{"echo", "@@args.txt", "@"}, `0 ["@args.txt" "@"]`},
+		{[]string{"echo", "--", "@args.txt"}, `0 ["@args.txt"]`},
+	} {
+		b.Reset()
+		if err := app.Run(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		if err := testOut(b.String(), tt.out+"\n"); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// disabled
+	b.Reset()
+	app.ResponseFiles = false
+	if err := app.Run([]string{"echo", "@args.txt"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(b.String(), `0 ["@args.txt"]`+"\n"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestResponseFileError(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"quote.txt": "foo\n'bar\n",
+		"loop.txt":  "@loop.txt\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	app := cli.NewCLI()
+	app.Stdout = io.Discard
+	app.Stderr = io.Discard
+	app.Dir = dir
+	app.ResponseFiles = true
+	for _, tt := range []struct {
+		args []string
+		file string
+		line int
+		err  error
+	}{
+		{[]string{"@noexist.txt"}, "noexist.txt", 0, os.ErrNotExist},
+		{[]string{"@quote.txt"}, "quote.txt", 2, cli.ErrQuote},
+		{[]string{"@loop.txt"}, "loop.txt", 1, cli.ErrResponseDepth},
+	} {
+		err := app.Run(tt.args)
+		var re cli.ResponseFileError
+		switch {
+		case !errors.As(err, &re):
+			t.Errorf("%v: expected ResponseFileError, got %#v", tt.args, err)
+		case re.File != tt.file || re.Line != tt.line || !errors.Is(err, tt.err):
+			t.Errorf("%v: unexpected error: %v", tt.args, err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+//
+// go.cli :: schema.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const SchemaVersion = 1
+
+type Schema struct {
+	Schema   int              `json:"schema"`
+	Name     string           `json:"name"`
+	Version  string           `json:"version,omitempty"`
+	Usage    []string         `json:"usage,omitempty"`
+	Desc     string           `json:"desc,omitempty"`
+	Epilog   string           `json:"epilog,omitempty"`
+	Examples []Example        `json:"examples,omitempty"`
+	Flags    []*FlagSchema    `json:"flags,omitempty"`
+	Cmds     []*CommandSchema `json:"commands,omitempty"`
+	Topics   []*TopicSchema   `json:"topics,omitempty"`
+}
+
+type CommandSchema struct {
+	Name     string           `json:"name"`
+	Aliases  []string         `json:"aliases,omitempty"`
+	Usage    []string         `json:"usage,omitempty"`
+	Desc     string           `json:"desc,omitempty"`
+	Epilog   string           `json:"epilog,omitempty"`
+	Examples []Example        `json:"examples,omitempty"`
+	Hidden   bool             `json:"hidden,omitempty"`
+	Flags    []*FlagSchema    `json:"flags,omitempty"`
+	Cmds     []*CommandSchema `json:"commands,omitempty"`
+}
+
+type FlagSchema struct {
+	Name    []string `json:"name"`
+	Type    string   `json:"type"`
+	Default string   `json:"default"`
+	Usage   string   `json:"usage,omitempty"`
+	MetaVar string   `json:"metavar,omitempty"`
+	EnvVar  string   `json:"env,omitempty"`
+	Choices []string `json:"choices,omitempty"`
+}
+
+type TopicSchema struct {
+	Name string `json:"name"`
+	Desc string `json:"desc,omitempty"`
+	Text string `json:"text"`
+}
+
+func NewSchema(ui *CLI) *Schema {
+	s := &Schema{
+		Schema:   SchemaVersion,
+		Name:     ui.Name,
+		Version:  ui.Version,
+		Usage:    usageList(ui.Usage),
+		Desc:     ui.Desc,
+		Epilog:   ui.Epilog,
+		Examples: ui.Examples,
+		Flags:    flagSchema(ui.Flags),
+		Cmds:     commandSchema(ui.Cmds),
+	}
+	for _, t := range topics(ui.Topics) {
+		s.Topics = append(s.Topics, &TopicSchema{
+			Name: t.Name,
+			Desc: t.Desc,
+			Text: Dedent(t.Text),
+		})
+	}
+	// flags which are added by CLI.Run
+	if ui.Flags == nil || (ui.Flags.Lookup("h") == nil && ui.Flags.Lookup("help") == nil) {
+		s.Flags = append(s.Flags, &FlagSchema{
+			Name:    []string{"h", "help"},
+			Type:    "bool",
+			Default: "false",
+			Usage:   "show help",
+		})
+	}
+	if ui.Flags == nil || ui.Flags.Lookup("version") == nil {
+		s.Flags = append(s.Flags, &FlagSchema{
+			Name:    []string{"version"},
+			Type:    "bool",
+			Default: "false",
+			Usage:   "show version information",
+		})
+	}
+	sort.SliceStable(s.Flags, func(i, j int) bool { return s.Flags[i].Name[0] < s.Flags[j].Name[0] })
+	return s
+}
+
+func WriteSchema(w io.Writer, ui *CLI) error {
+	b, err := json.MarshalIndent(NewSchema(ui), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+func NewSchemaCommand() *Command {
+	return &Command{
+		Name:   []string{"schema"},
+		Desc:   "show the command line interface as JSON",
+		Hidden: true,
+		Flags:  NewFlagSet(),
+		Action: func(ctx *Context) error {
+			if len(ctx.Args) > 0 {
+				return ErrArgs
+			}
+			return WriteSchema(ctx.UI.Stdout, ctx.UI)
+		},
+	}
+}
+
+func commandSchema(list []*Command) []*CommandSchema {
+	sorted := make(CommandSlice, len(list))
+	copy(sorted, list)
+	sorted.Sort()
+	var schema []*CommandSchema
+	for _, c := range sorted {
+		var aliases []string
+		if len(c.Name) > 1 {
+			aliases = c.Name[1:]
+		}
+		schema = append(schema, &CommandSchema{
+			Name:     c.Name[0],
+			Aliases:  aliases,
+			Usage:    usageList(c.Usage),
+			Desc:     c.Desc,
+			Epilog:   c.Epilog,
+			Examples: c.Examples,
+			Hidden:   c.Hidden,
+			Flags:    flagSchema(c.Flags),
+			Cmds:     commandSchema(c.Cmds),
+		})
+	}
+	return schema
+}
+
+func flagSchema(fs *FlagSet) []*FlagSchema {
+	var schema []*FlagSchema
+	for _, f := range flags(fs) {
+		s := &FlagSchema{
+			Name:    f.Name,
+			Default: f.Default,
+			Usage:   f.Usage,
+			MetaVar: strings.TrimSpace(MetaVar(f)),
+			EnvVar:  f.EnvVar,
+		}
+		switch v := f.Value.(type) {
+		case *choiceValue:
+			s.Type = "choice"
+			for k := range v.choices {
+				s.Choices = append(s.Choices, k)
+			}
+			sort.Strings(s.Choices)
+		default:
+			switch f.Value.Get().(type) {
+			case bool:
+				s.Type = "bool"
+			case time.Duration:
+				s.Type = "duration"
+			case float64:
+				s.Type = "float64"
+			case int:
+				s.Type = "int"
+			case int64:
+				s.Type = "int64"
+			case string:
+				s.Type = "string"
+			case uint:
+				s.Type = "uint"
+			case uint64:
+				s.Type = "uint64"
+			default:
+				s.Type = "value"
+			}
+		}
+		schema = append(schema, s)
+	}
+	return schema
+}
+
+func usageList(u any) []string {
+	switch v := u.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []string:
+		usage := make([]string, len(v))
+		copy(usage, v)
+		return usage
+	default:
+		panic(fmt.Sprintf("unknown type '%T'", v))
+	}
+}
@@ -0,0 +1,165 @@
+//
+// go.cli :: schema_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hattya/go.cli"
+)
+
+func TestSchema(t *testing.T) {
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Version = "1.0"
+	app.Usage = "<command>"
+	app.Flags.BoolEnv("APP_BOOL", "b, bool", false, "bool flag")
+	app.Flags.Duration("duration", time.Second, "")
+	app.Flags.Choice("choice", 1, map[string]any{"1": 1, "2": 2}, "")
+	app.Flags.Var("var", new(value), "")
+	app.Flags.MetaVar("duration", "=<d>")
+	app.Add(&cli.Command{
+		Name:  []string{"cmd", "c"},
+		Usage: []string{"foo", "bar"},
+		Desc:  "desc",
+		Flags: cli.NewFlagSet(),
+		Cmds: []*cli.Command{
+			{Name: []string{"subcmd"}},
+		},
+	})
+	app.Add(cli.NewSchemaCommand())
+	app.AddTopic(&cli.Topic{
+		Name: "topic",
+		Desc: "desc",
+		Text: `
+			text
+		`,
+	})
+
+	var b bytes.Buffer
+	app.Stdout = &b
+	if err := app.Run([]string{"schema"}); err != nil {
+		t.Fatal(err)
+	}
+	var s cli.Schema
+	if err := json.Unmarshal(b.Bytes(), &s); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&s, cli.NewSchema(app)) {
+		t.Errorf("unexpected schema: %v", b.String())
+	}
+
+	e := &cli.Schema{
+		Schema:  cli.SchemaVersion,
+		Name:    "app",
+		Version: "1.0",
+		Usage:   []string{"<command>"},
+		Flags: []*cli.FlagSchema{
+			{
+				Name:    []string{"b", "bool"},
+				Type:    "bool",
+				Default: "false",
+				Usage:   "bool flag",
+				EnvVar:  "APP_BOOL",
+			},
+			{
+				Name:    []string{"choice"},
+				Type:    "choice",
+				Default: "1",
+				MetaVar: "<choice>",
+				Choices: []string{"1", "2"},
+			},
+			{
+				Name:    []string{"duration"},
+				Type:    "duration",
+				Default: "1s",
+				MetaVar: "=<d>",
+			},
+			{
+				Name:    []string{"h", "help"},
+				Type:    "bool",
+				Default: "false",
+				Usage:   "show help",
+			},
+			{
+				Name:    []string{"var"},
+				Type:    "string",
+				MetaVar: "<var>",
+			},
+			{
+				Name:    []string{"version"},
+				Type:    "bool",
+				Default: "false",
+				Usage:   "show version information",
+			},
+		},
+		Cmds: []*cli.CommandSchema{
+			{
+				Name:    "cmd",
+				Aliases: []string{"c"},
+				Usage:   []string{"foo", "bar"},
+				Desc:    "desc",
+				Cmds: []*cli.CommandSchema{
+					{
+						Name: "subcmd",
+					},
+				},
+			},
+			{
+				Name:   "schema",
+				Desc:   "show the command line interface as JSON",
+				Hidden: true,
+			},
+		},
+		Topics: []*cli.TopicSchema{
+			{
+				Name: "topic",
+				Desc: "desc",
+				Text: "text\n",
+			},
+		},
+	}
+	if g := cli.NewSchema(app); !reflect.DeepEqual(g, e) {
+		g, _ := json.Marshal(g)
+		e, _ := json.Marshal(e)
+		t.Errorf("expected %s, got %s", e, g)
+	}
+
+	// before CLI.Run
+	app = cli.NewCLI()
+	if g, e := len(cli.NewSchema(app).Flags), 2; g != e {
+		t.Errorf("expected %v, got %v", e, g)
+	}
+}
+
+func TestSchemaCommand(t *testing.T) {
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdout = &b
+	app.Stderr = &b
+	app.Add(cli.NewSchemaCommand())
+	app.Add(&cli.Command{
+		Name: []string{"status"},
+	})
+
+	// hidden commands are excluded from prefix match
+	if err := app.Run([]string{"s"}); err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("unexpected output: %q", b.String())
+	}
+	if err := app.Run([]string{"schema", "_"}); err != cli.ErrArgs {
+		t.Errorf("expected ErrArgs, got %#v", err)
+	}
+}
@@ -0,0 +1,228 @@
+//
+// go.cli :: script.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+type ErrorPolicy int
+
+const (
+	StopOnError ErrorPolicy = iota
+	ContinueOnError
+)
+
+type ScriptError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e ScriptError) Error() string { return fmt.Sprintf("%v:%v: %v", e.File, e.Line, e.Err) }
+func (e ScriptError) Unwrap() error { return e.Err }
+
+func (fs *FlagSet) ScriptFlag(name, usage string) *Flag {
+	if name == "" {
+		name = "script"
+	}
+	if usage == "" {
+		usage = "run commands from the file"
+	}
+	f := fs.String(name, "", usage)
+	f.MetaVar = " <file>"
+	f.role = roleScript
+	return f
+}
+
+func (ui *CLI) runScript(ctx *Context, name string) error {
+	if len(ctx.Args) > 0 {
+		return ctx.ErrorHandler(ErrArgs)
+	}
+
+	var r io.Reader
+	if name == "-" {
+		name = "<stdin>"
+		r = ui.Stdin
+	} else {
+		path := name
+		if ui.Dir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(ui.Dir, path)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return ctx.ErrorHandler(err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	global := globalFlags(ui.Flags)
+	var errs Errors
+	s := bufio.NewScanner(r)
+	n := 0
+	for {
+		if ui.ctx.Err() != nil {
+			errs = append(errs, ctx.ErrorHandler(Interrupt{}))
+			break
+		}
+		line, lineno, ok := scanLine(s, &n)
+		if !ok {
+			if err := s.Err(); err != nil {
+				errs = append(errs, ctx.ErrorHandler(err))
+			}
+			break
+		}
+		wrap := func(err error) error {
+			return ScriptError{
+				File: name,
+				Line: lineno,
+				Err:  err,
+			}
+		}
+
+		args, err := Split(expandEnv(stripComment(line), ui.Getenv))
+		switch {
+		case err != nil:
+			err = ctx.ErrorHandler(wrap(err))
+		case len(args) == 0:
+			continue
+		default:
+			err = ui.exec(ctx.Data, args, global, wrap)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			if ui.ScriptErrors == StopOnError || errors.Is(err, Interrupt{}) {
+				break
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// scanLine returns the next logical line which may be continued by a
+// trailing backslash, and its line number.
+func scanLine(s *bufio.Scanner, n *int) (string, int, bool) {
+	var b strings.Builder
+	lineno := 0
+	for s.Scan() {
+		*n++
+		if lineno == 0 {
+			lineno = *n
+		}
+		line := strings.TrimSuffix(s.Text(), "\r")
+		if i := len(line) - len(strings.TrimRight(line, `\`)); i%2 == 1 {
+			b.WriteString(line[:len(line)-1])
+			continue
+		}
+		b.WriteString(line)
+		return b.String(), lineno, true
+	}
+	return b.String(), lineno, lineno > 0
+}
+
+func stripComment(s string) string {
+	var quote rune
+	esc := false
+	prev := ' '
+	for i, r := range s {
+		switch {
+		case esc:
+			esc = false
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' {
+				esc = true
+			}
+		case r == '\\':
+			esc = true
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '#' && unicode.IsSpace(prev):
+			return s[:i]
+		}
+		prev = r
+	}
+	return s
+}
+
+// expandEnv replaces $VAR and ${VAR} outside single quotes with the values
+// of the environment variables. The values are escaped so that they are not
+// split by Split.
+func expandEnv(s string, getenv func(string) string) string {
+	var b strings.Builder
+	var quote rune
+	esc := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case esc:
+			esc = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			}
+		case c == '\\':
+			esc = true
+		case c == '\'' && quote == 0:
+			quote = '\''
+		case c == '"':
+			if quote == 0 {
+				quote = '"'
+			} else {
+				quote = 0
+			}
+		case c == '$':
+			if name, n := envName(s[i+1:]); n > 0 {
+				b.WriteString(escape(getenv(name), quote == '"'))
+				i += n
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func envName(s string) (string, int) {
+	if strings.HasPrefix(s, "{") {
+		if i := strings.IndexByte(s, '}'); i > 1 {
+			return s[1:i], i + 1
+		}
+		return "", 0
+	}
+	i := 0
+	for i < len(s) && (s[i] == '_' || 'A' <= s[i] && s[i] <= 'Z' || 'a' <= s[i] && s[i] <= 'z' || 0 < i && '0' <= s[i] && s[i] <= '9') {
+		i++
+	}
+	return s[:i], i
+}
+
+func escape(s string, quoted bool) string {
+	var b strings.Builder
+	for _, r := range s {
+		if quoted {
+			if strings.ContainsRune(`"\$`+"`", r) {
+				b.WriteRune('\\')
+			}
+		} else if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_./:=,@+%", r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,175 @@
+//
+// go.cli :: script_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hattya/go.cli"
+)
+
+const script = `# comment
+echo foo # comment
+echo 'foo # bar' \
+     -n 1 \\
+echo "$FOO" $FOO '$FOO' \$FOO ${FOO}bar $UNDEFINED
+
+echo a\
+b
+fail
+echo unreachable
+`
+
+func TestScript(t *testing.T) {
+	t.Setenv("FOO", `"foo bar"`)
+
+	path := filepath.Join(t.TempDir(), "script.txt")
+	if err := os.WriteFile(path, []byte(script), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Stdout = &stdout
+	app.Stderr = &stderr
+	app.Flags.Bool("v", false, "")
+	app.Flags.ScriptFlag("f, script", "")
+	flags := cli.NewFlagSet()
+	flags.Int("n", 0, "")
+	app.Add(&cli.Command{
+		Name:  []string{"echo"},
+		Flags: flags,
+		Action: func(ctx *cli.Context) error {
+			ctx.UI.Printf("%v %v %q\n", ctx.Bool("v"), ctx.Int("n"), ctx.Args)
+			return nil
+		},
+	})
+	app.Add(&cli.Command{
+		Name:  []string{"fail"},
+		Flags: cli.NewFlagSet(),
+		Action: func(ctx *cli.Context) error {
+			return errors.New("failed")
+		},
+	})
+	err := app.Run([]string{"-f", path})
+	var se cli.ScriptError
+	switch {
+	case !errors.As(err, &se):
+		t.Fatalf("expected ScriptError, got %#v", err)
+	case se.File != path || se.Line != 9 || se.Err.Error() != "failed":
+		t.Errorf("unexpected error: %v", err)
+	}
+	out := `false 0 ["foo"]` + "\n" +
+		`false 0 ["foo # bar" "-n" "1" "\\"]` + "\n" +
+		`false 0 ["\"foo bar\"" "\"foo bar\"" "$FOO" "$FOO" "\"foo bar\"bar"]` + "\n" +
+		`false 0 ["ab"]` + "\n"
+	if err := testOut(stdout.String(), out); err != nil {
+		t.Error(err)
+	}
+	if err := testOut(stderr.String(), "app fail: "+path+":9: failed\n"); err != nil {
+		t.Error(err)
+	}
+
+	// continue on error
+	stdout.Reset()
+	stderr.Reset()
+	app.ScriptErrors = cli.ContinueOnError
+	if err := app.Run([]string{"-v", "-f", path}); err == nil {
+		t.Error("expected error")
+	}
+	if !strings.HasSuffix(stdout.String(), `true 0 ["unreachable"]`+"\n") {
+		t.Errorf("unexpected output: %q", stdout.String())
+	}
+	if err := testOut(stderr.String(), "app fail: "+path+":9: failed\n"); err != nil {
+		t.Error(err)
+	}
+
+	// stdin
+	stderr.Reset()
+	app.Stdin = strings.NewReader("echo -x\necho 'foo\n")
+	switch err := app.Run([]string{"-f", "-"}).(type) {
+	case cli.Errors:
+		if g, e := len(err), 2; g != e {
+			t.Errorf("expected %v errors, got %v", e, g)
+		}
+	default:
+		t.Errorf("expected Errors, got %#v", err)
+	}
+	for _, s := range []string{
+		"app echo: <stdin>:1: flag provided but not defined: -x\n",
+		"app: <stdin>:2: cli: unterminated quoted string\n",
+	} {
+		if !strings.Contains(stderr.String(), s) {
+			t.Errorf("expected %q in %q", s, stderr.String())
+		}
+	}
+}
+
+func TestScriptError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.txt")
+	if err := os.WriteFile(path, []byte("interrupt\ninterrupt\n"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	app := cli.NewCLI()
+	app.Stdout = io.Discard
+	app.Stderr = io.Discard
+	app.Flags.ScriptFlag("f, script", "")
+	app.Add(&cli.Command{
+		Name:  []string{"interrupt"},
+		Flags: cli.NewFlagSet(),
+		Action: func(ctx *cli.Context) error {
+			ctx.Interrupt()
+			return nil
+		},
+	})
+	if err := app.Run([]string{"-f", path + ".noexist"}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected ErrNotExist, got %#v", err)
+	}
+
+	if err := app.Run([]string{"-f", path, "interrupt"}); err != cli.ErrArgs {
+		t.Errorf("expected ErrArgs, got %#v", err)
+	}
+
+	// relative to CLI.Dir
+	app.Dir = filepath.Dir(path)
+	if err := app.Run([]string{"-f", filepath.Base(path)}); !errors.Is(err, cli.Interrupt{}) {
+		t.Errorf("expected Interrupt, got %#v", err)
+	}
+	app.Dir = ""
+
+	app.ScriptErrors = cli.ContinueOnError
+	if err := app.Run([]string{"-f", path}); !errors.Is(err, cli.Interrupt{}) {
+		t.Errorf("expected Interrupt, got %#v", err)
+	}
+}
+
+func TestScriptFlag(t *testing.T) {
+	// a flag which is not registered by ScriptFlag is not a script
+	var script string
+	app := cli.NewCLI()
+	app.Stdout = io.Discard
+	app.Stderr = io.Discard
+	app.Flags.String("script", "", "")
+	app.Action = func(ctx *cli.Context) error {
+		script = ctx.String("script")
+		return nil
+	}
+	if err := app.Run([]string{"-script", "init.lua"}); err != nil {
+		t.Fatal(err)
+	}
+	if g, e := script, "init.lua"; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
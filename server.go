@@ -0,0 +1,372 @@
+//
+// go.cli :: server.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+)
+
+// frame types
+const (
+	frameRequest   = 'r'
+	frameStdin     = '0'
+	frameStdout    = '1'
+	frameStderr    = '2'
+	frameInterrupt = 'i'
+	frameExit      = 'x'
+	frameWant      = 'w' // the server waits for stdin
+)
+
+const maxFrameSize = 1 << 20
+
+type request struct {
+	Args []string `json:"args"`
+	Env  []string `json:"env"`
+	Dir  string   `json:"dir"`
+}
+
+func writeFrame(w io.Writer, typ byte, b []byte) error {
+	h := make([]byte, 5, 5+len(b))
+	h[0] = typ
+	binary.BigEndian.PutUint32(h[1:], uint32(len(b)))
+	_, err := w.Write(append(h, b...))
+	return err
+}
+
+func readFrame(r io.Reader) (byte, []byte, error) {
+	var h [5]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		return 0, nil, err
+	}
+	n := binary.BigEndian.Uint32(h[1:])
+	if n > maxFrameSize {
+		return 0, nil, fmt.Errorf("cli: frame too large: %v", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return 0, nil, err
+	}
+	return h[0], b, nil
+}
+
+func NewServerCommand(path string) *Command {
+	return &Command{
+		Name:   []string{"server"},
+		Desc:   "start a command server",
+		Hidden: true,
+		Flags:  NewFlagSet(),
+		Action: func(ctx *Context) error {
+			if len(ctx.Args) > 0 {
+				return ErrArgs
+			}
+			return ctx.UI.ListenAndServe(path)
+		},
+	}
+}
+
+func (ui *CLI) ListenAndServe(path string) error {
+	if c, err := net.Dial("unix", path); err == nil {
+		c.Close()
+		return fmt.Errorf("cli: server is already running on %v", path)
+	}
+	os.Remove(path)
+	l, err := listenUnix(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	return ui.Serve(l)
+}
+
+func (ui *CLI) Serve(l net.Listener) error {
+	ctx := ui.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	stop := context.AfterFunc(ctx, func() { l.Close() })
+	defer stop()
+	defer l.Close()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return Interrupt{}
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ui.serveConn(ctx, conn)
+		}()
+	}
+}
+
+func (ui *CLI) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	typ, b, err := readFrame(conn)
+	if err != nil || typ != frameRequest {
+		return
+	}
+	var req request
+	if json.Unmarshal(b, &req) != nil {
+		return
+	}
+
+	// serve from the CLI before the run of the server which has parsed
+	// its flags
+	if ui.run != nil {
+		ui = ui.run.origin
+	}
+	var mu sync.Mutex
+	c := *ui
+	c.Stdin = &connReader{
+		want: func() {
+			mu.Lock()
+			defer mu.Unlock()
+			writeFrame(conn, frameWant, nil)
+		},
+	}
+	c.Stdout = &connWriter{mu: &mu, conn: conn, typ: frameStdout}
+	c.Stderr = &connWriter{mu: &mu, conn: conn, typ: frameStderr}
+	c.Env = req.Env
+	c.Dir = req.Dir
+	c.HandleSignals = false
+	c.in = new(input)
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	defer c.cancel()
+
+	go func() {
+		stdin := c.Stdin.(*connReader)
+		for {
+			typ, b, err := readFrame(conn)
+			switch {
+			case err != nil:
+				// client has gone
+				stdin.close(err)
+				c.cancel()
+				return
+			case typ == frameStdin:
+				if len(b) == 0 {
+					stdin.close(io.EOF)
+				} else {
+					stdin.write(b)
+				}
+			case typ == frameInterrupt:
+				c.cancel()
+			}
+		}
+	}()
+
+	code := ExitCode(c.Run(req.Args))
+	var e [4]byte
+	binary.BigEndian.PutUint32(e[:], uint32(int32(code)))
+	mu.Lock()
+	writeFrame(conn, frameExit, e[:])
+	mu.Unlock()
+}
+
+// connReader is the stdin of a request. It asks the client for input by
+// calling want only when it has no data to read, so that the client does
+// not read its stdin which is not consumed by the command.
+type connReader struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	buf     []byte
+	err     error
+	want    func()
+	waiting bool
+}
+
+func (r *connReader) init() {
+	if r.cond == nil {
+		r.cond = sync.NewCond(&r.mu)
+	}
+}
+
+func (r *connReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.init()
+	for len(r.buf) == 0 && r.err == nil {
+		if !r.waiting {
+			r.waiting = true
+			r.want()
+		}
+		r.cond.Wait()
+	}
+	if len(r.buf) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *connReader) write(b []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.init()
+	r.buf = append(r.buf, b...)
+	r.waiting = false
+	r.cond.Broadcast()
+}
+
+func (r *connReader) close(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.init()
+	if r.err == nil {
+		r.err = err
+	}
+	r.cond.Broadcast()
+}
+
+type connWriter struct {
+	mu   *sync.Mutex
+	conn net.Conn
+	typ  byte
+}
+
+func (w *connWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for b := p; len(b) > 0; {
+		n := min(len(b), maxFrameSize)
+		if err := writeFrame(w.conn, w.typ, b[:n]); err != nil {
+			return len(p) - len(b), err
+		}
+		b = b[n:]
+	}
+	return len(p), nil
+}
+
+// Client runs commands on a server. Its Stdin is read only while the command
+// waits for input. A read which is in progress when the command exits is
+// abandoned, and the data read by it are discarded.
+type Client struct {
+	Path   string
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Env    []string
+	Dir    string
+}
+
+var ErrServer = errors.New("cli: connection to server is closed")
+
+func (cl *Client) Run(ctx context.Context, args []string) (int, error) {
+	conn, err := net.Dial("unix", cl.Path)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	req := request{
+		Args: args,
+		Env:  cl.Env,
+		Dir:  cl.Dir,
+	}
+	if req.Args == nil {
+		req.Args = []string{}
+	}
+	if req.Env == nil {
+		req.Env = os.Environ()
+	}
+	if req.Dir == "" {
+		if req.Dir, err = os.Getwd(); err != nil {
+			return 0, err
+		}
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return 0, err
+	}
+	var mu sync.Mutex
+	if err := writeFrame(conn, frameRequest, b); err != nil {
+		return 0, err
+	}
+
+	stdin := cl.Stdin
+	if stdin == nil {
+		stdin = os.Stdin
+	}
+	want := make(chan struct{}, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		b := make([]byte, 32*1024)
+		for {
+			select {
+			case <-want:
+			case <-done:
+				return
+			}
+			n, err := stdin.Read(b)
+			mu.Lock()
+			if n > 0 {
+				writeFrame(conn, frameStdin, b[:n])
+			}
+			if err != nil {
+				writeFrame(conn, frameStdin, nil)
+			}
+			mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	stop := context.AfterFunc(ctx, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		writeFrame(conn, frameInterrupt, nil)
+	})
+	defer stop()
+
+	stdout := cl.Stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	stderr := cl.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+	for {
+		typ, b, err := readFrame(conn)
+		switch {
+		case err == io.EOF:
+			return 0, ErrServer
+		case err != nil:
+			return 0, err
+		case typ == frameStdout:
+			stdout.Write(b)
+		case typ == frameStderr:
+			stderr.Write(b)
+		case typ == frameWant:
+			select {
+			case want <- struct{}{}:
+			default:
+			}
+		case typ == frameExit && len(b) == 4:
+			return int(int32(binary.BigEndian.Uint32(b))), nil
+		}
+	}
+}
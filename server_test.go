@@ -0,0 +1,320 @@
+//
+// go.cli :: server_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hattya/go.cli"
+)
+
+func startServer(t *testing.T) (*cli.CLI, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "cli.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skip(err)
+	}
+
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Add(&cli.Command{
+		Name:  []string{"env"},
+		Flags: cli.NewFlagSet(),
+		Action: func(ctx *cli.Context) error {
+			dir, err := ctx.UI.Getwd()
+			if err != nil {
+				return err
+			}
+			ctx.UI.Printf("%v %v %v\n", ctx.Args, ctx.UI.Getenv("FOO"), dir)
+			return nil
+		},
+	})
+	app.Add(&cli.Command{
+		Name:  []string{"cat"},
+		Flags: cli.NewFlagSet(),
+		Action: func(ctx *cli.Context) error {
+			for {
+				s, err := ctx.UI.Prompt("")
+				switch {
+				case err == io.EOF:
+					return nil
+				case err != nil:
+					return err
+				}
+				ctx.UI.Println(strings.ToUpper(s))
+			}
+		},
+	})
+	app.Add(&cli.Command{
+		Name:  []string{"wait"},
+		Flags: cli.NewFlagSet(),
+		Action: func(ctx *cli.Context) error {
+			ctx.UI.Println("waiting")
+			<-ctx.Context().Done()
+			return cli.Interrupt{}
+		},
+	})
+	app.Add(&cli.Command{
+		Name:  []string{"fail"},
+		Flags: cli.NewFlagSet(),
+		Action: func(ctx *cli.Context) error {
+			return errors.New("failed")
+		},
+	})
+
+	done := make(chan error)
+	go func() {
+		done <- app.Serve(l)
+	}()
+	t.Cleanup(func() {
+		app.Interrupt()
+		if err := <-done; err != (cli.Interrupt{}) {
+			t.Errorf("expected Interrupt, got %#v", err)
+		}
+	})
+	return app, path
+}
+
+func TestServer(t *testing.T) {
+	_, path := startServer(t)
+
+	var stdout, stderr strings.Builder
+	cl := &cli.Client{
+		Path:   path,
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Env:    []string{"FOO=foo"},
+		Dir:    "/dir",
+	}
+	for _, tt := range []struct {
+		args   []string
+		stdin  string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{
+			args:   []string{"env", "a", "b"},
+			stdout: "[a b] foo /dir\n",
+		},
+		{
+			args:   []string{"cat"},
+			stdin:  "foo\nbar\n",
+			stdout: "FOO\nBAR\n",
+		},
+		{
+			args:   []string{"fail"},
+			code:   1,
+			stderr: "app fail: failed\n",
+		},
+		{
+			args:   []string{"-x"},
+			code:   2,
+			stdout: "usage: app\n\ncommands:\n\n  cat\n  env\n  fail\n  wait\n\noptions:\n\n  -h, --help    show help\n  --version     show version information\n\n",
+			stderr: "app: flag provided but not defined: -x\n",
+		},
+	} {
+		stdout.Reset()
+		stderr.Reset()
+
+		cl.Stdin = strings.NewReader(tt.stdin)
+		code, err := cl.Run(context.Background(), tt.args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g, e := code, tt.code; g != e {
+			t.Errorf("%v: exit code = %v, expected %v", tt.args, g, e)
+		}
+		if err := testOut(stdout.String(), tt.stdout); err != nil {
+			t.Error(err)
+		}
+		if !strings.HasPrefix(stderr.String(), tt.stderr) {
+			t.Errorf("%v: unexpected stderr: %q", tt.args, stderr.String())
+		}
+	}
+}
+
+func TestServerStdin(t *testing.T) {
+	_, path := startServer(t)
+
+	var b strings.Builder
+	stdin := strings.NewReader("foo\n")
+	cl := &cli.Client{
+		Path:   path,
+		Stdin:  stdin,
+		Stdout: &b,
+		Stderr: &b,
+	}
+	// stdin is not read by the command which does not read it
+	if _, err := cl.Run(context.Background(), []string{"env"}); err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stdin.Len(), 4; g != e {
+		t.Errorf("expected %v bytes unread, got %v", e, g)
+	}
+
+	b.Reset()
+	if _, err := cl.Run(context.Background(), []string{"cat"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(b.String(), "FOO\n"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestServerInterrupt(t *testing.T) {
+	_, path := startServer(t)
+
+	r, w := io.Pipe()
+	defer w.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stdout := &notifyWriter{ch: make(chan struct{}, 1)}
+	cl := &cli.Client{
+		Path:   path,
+		Stdin:  r,
+		Stdout: stdout,
+		Stderr: io.Discard,
+	}
+	go func() {
+		<-stdout.ch
+		cancel()
+	}()
+	code, err := cl.Run(ctx, []string{"wait"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := code, 130; g != e {
+		t.Errorf("exit code = %v, expected %v", g, e)
+	}
+}
+
+func TestServerShutdown(t *testing.T) {
+	app, path := startServer(t)
+
+	stdout := &notifyWriter{ch: make(chan struct{}, 1)}
+	cl := &cli.Client{
+		Path:   path,
+		Stdin:  strings.NewReader(""),
+		Stdout: stdout,
+		Stderr: io.Discard,
+	}
+	go func() {
+		<-stdout.ch
+		app.Interrupt()
+	}()
+	code, err := cl.Run(context.Background(), []string{"wait"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := code, 130; g != e {
+		t.Errorf("exit code = %v, expected %v", g, e)
+	}
+}
+
+func TestServerCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cli.sock")
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Stdout = io.Discard
+	app.Stderr = io.Discard
+	app.Flags.Bool("v", false, "")
+	app.Add(cli.NewServerCommand(path))
+	app.Add(&cli.Command{
+		Name:  []string{"flag"},
+		Flags: cli.NewFlagSet(),
+		Action: func(ctx *cli.Context) error {
+			ctx.UI.Println(ctx.Bool("v"))
+			return nil
+		},
+	})
+	done := make(chan error)
+	go func() {
+		done <- app.Run([]string{"-v", "server"})
+	}()
+	defer func() {
+		app.Interrupt()
+		if err := <-done; err != (cli.Interrupt{}) {
+			t.Errorf("expected Interrupt, got %#v", err)
+		}
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected ErrNotExist, got %#v", err)
+		}
+	}()
+	for i := 0; ; i++ {
+		c, err := net.Dial("unix", path)
+		if err == nil {
+			c.Close()
+			break
+		} else if i == 100 {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g, e := fi.Mode().Perm(), os.FileMode(0o600); g != e {
+			t.Errorf("expected %v, got %v", e, g)
+		}
+	}
+	if m, err := filepath.Glob(filepath.Join(filepath.Dir(path), ".cli-*")); err != nil || len(m) > 0 {
+		t.Errorf("unexpected files: %v", m)
+	}
+
+	// flags of the server are not inherited
+	var b strings.Builder
+	cl := &cli.Client{
+		Path:   path,
+		Stdin:  strings.NewReader(""),
+		Stdout: &b,
+		Stderr: &b,
+	}
+	for _, tt := range []struct {
+		args []string
+		out  string
+	}{
+		{[]string{"flag"}, "false\n"},
+		{[]string{"-v", "flag"}, "true\n"},
+	} {
+		b.Reset()
+		if _, err := cl.Run(context.Background(), tt.args); err != nil {
+			t.Fatal(err)
+		}
+		if err := testOut(b.String(), tt.out); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+type notifyWriter struct {
+	ch chan struct{}
+}
+
+func (w *notifyWriter) Write(p []byte) (int, error) {
+	select {
+	case w.ch <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
@@ -0,0 +1,244 @@
+//
+// go.cli :: shell.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+var ErrQuote = errors.New("cli: unterminated quoted string")
+
+func Split(s string) ([]string, error) {
+	var args []string
+	var b strings.Builder
+	arg := false
+	var quote rune
+	esc := false
+	for _, r := range s {
+		switch {
+		case esc:
+			if quote == '"' && !strings.ContainsRune(`"\$`+"`", r) {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+			esc = false
+		case quote == '\'':
+			if r == quote {
+				quote = 0
+			} else {
+				b.WriteRune(r)
+			}
+		case r == '\\':
+			arg = true
+			esc = true
+		case quote == '"':
+			if r == quote {
+				quote = 0
+			} else {
+				b.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			arg = true
+			quote = r
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if arg {
+				args = append(args, b.String())
+				b.Reset()
+				arg = false
+			}
+		default:
+			arg = true
+			b.WriteRune(r)
+		}
+	}
+	if esc || quote != 0 {
+		return nil, ErrQuote
+	}
+	if arg {
+		args = append(args, b.String())
+	}
+	return args, nil
+}
+
+type Shell struct {
+	Prompt  string
+	History string
+}
+
+func NewShellCommand() *Command {
+	return &Command{
+		Name:   []string{"shell"},
+		Desc:   "start an interactive shell",
+		Flags:  NewFlagSet(),
+		Action: new(Shell).Run,
+	}
+}
+
+func (sh *Shell) Run(ctx *Context) error {
+	if len(ctx.Args) > 0 {
+		return ErrArgs
+	}
+
+	ui := ctx.UI
+	prompt := sh.Prompt
+	if prompt == "" {
+		prompt = ui.Name + "> "
+	}
+	history := sh.History
+	if history == "" {
+		history = "shell"
+	}
+	global := globalFlags(ui.Flags)
+
+	for {
+		line, err := ui.Prompt(prompt, PromptHistory(history), PromptCompleter(ui.completeCommand))
+		switch {
+		case err == io.EOF:
+			ui.Println()
+			return nil
+		case errors.Is(err, Interrupt{}) && ui.ctx.Err() == nil:
+			// Ctrl-C discards the current line
+			ui.Println()
+			continue
+		case err != nil:
+			return err
+		}
+		args, err := Split(line)
+		switch {
+		case err != nil:
+			ctx.ErrorHandler(err)
+			continue
+		case len(args) == 0:
+			continue
+		case len(args) == 1 && (args[0] == "exit" || args[0] == "quit"):
+			if _, err := FindCommand(ui.Cmds, args[0]); err != nil {
+				return nil
+			}
+		}
+
+		if err := ui.exec(ctx.Data, args, global, nil); errors.Is(err, Interrupt{}) {
+			return err
+		}
+	}
+}
+
+// exec runs args as a separate invocation, and returns the error which has
+// been handled by ErrorHandler.
+func (ui *CLI) exec(data any, args []string, global map[string]string, wrap func(error) error) error {
+	ui.resetFlags()
+	for n, v := range global {
+		ui.Flags.Set(n, v)
+	}
+	ctx := &Context{
+		UI:   ui,
+		Cmds: ui.Cmds,
+		Data: data,
+	}
+	err := ui.runLine(ctx, args)
+	if ui.ctx.Err() != nil && !errors.Is(err, Interrupt{}) {
+		err = Interrupt{}
+	}
+	if err != nil && wrap != nil {
+		err = wrap(err)
+	}
+	err = ctx.ErrorHandler(err)
+	if cerr := ctx.cleanup(); err == nil || errors.Is(cerr, ErrGracePeriod) {
+		err = cerr
+	}
+	return err
+}
+
+func (ui *CLI) runLine(ctx *Context, args []string) error {
+	if err := ui.Flags.Parse(args); err != nil {
+		return err
+	}
+	ctx.Flags = ui.Flags
+	ctx.Args = ui.Flags.Args()
+	switch {
+	case ui.help && ctx.Bool("help"):
+		return showHelp(ctx)
+	case ui.version && ctx.Bool("version"):
+		return Version(ctx)
+	}
+	return ui.action(ctx, args)
+}
+
+// globalFlags returns the flags which are specified before a command.
+func globalFlags(fs *FlagSet) map[string]string {
+	global := make(map[string]string)
+	fs.Visit(func(f *Flag) {
+		global[f.Name[0]] = f.Value.String()
+	})
+	return global
+}
+
+func (ui *CLI) completeCommand(line string) []string {
+	words := strings.Fields(line)
+	if len(words) == 0 || strings.HasSuffix(line, " ") {
+		words = append(words, "")
+	}
+	prefix := line[:len(line)-len(words[len(words)-1])]
+
+	cmds := ui.Cmds
+	var fs []*FlagSet
+	fs = append(fs, ui.Flags)
+	var help, topic bool
+	for _, w := range words[:len(words)-1] {
+		if strings.HasPrefix(w, "-") {
+			continue
+		}
+		c, err := FindCommand(cmds, w)
+		if err != nil {
+			return nil
+		}
+		topic = false
+		if !help {
+			fs = append(fs, c.Flags)
+		}
+		if c.help && !help {
+			// complete the commands which are shown by the help command, and
+			// the help topics when it is at the top level
+			help = true
+			topic = len(fs) == 2
+			continue
+		}
+		cmds = c.Cmds
+	}
+
+	var list []string
+	if strings.HasPrefix(words[len(words)-1], "-") {
+		for _, s := range fs {
+			for _, f := range flags(s) {
+				for _, n := range f.Name {
+					if len(n) == 1 {
+						list = append(list, prefix+"-"+n+" ")
+					} else {
+						list = append(list, prefix+"--"+n+" ")
+					}
+				}
+			}
+		}
+	} else {
+		for _, c := range cmds {
+			if !c.Hidden {
+				for _, n := range c.Name {
+					list = append(list, prefix+n+" ")
+				}
+			}
+		}
+		if topic {
+			for _, t := range topics(ui.Topics) {
+				list = append(list, prefix+t.Name+" ")
+			}
+		}
+	}
+	return list
+}
@@ -0,0 +1,239 @@
+//
+// go.cli :: shell_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hattya/go.cli"
+)
+
+var splitTests = []struct {
+	in   string
+	args []string
+	err  error
+}{
+	{
+		in: "",
+	},
+	{
+		in: " \t ",
+	},
+	{
+		in:   "foo bar  baz",
+		args: []string{"foo", "bar", "baz"},
+	},
+	{
+		in:   `'foo bar' "baz \"qux\"" ''`,
+		args: []string{"foo bar", `baz "qux"`, ""},
+	},
+	{
+		in:   `foo\ bar "\n" '\n'`,
+		args: []string{"foo bar", `\n`, `\n`},
+	},
+	{
+		in:   `foo'bar'"baz"`,
+		args: []string{"foobarbaz"},
+	},
+	{
+		in:  `'foo`,
+		err: cli.ErrQuote,
+	},
+	{
+		in:  `"foo`,
+		err: cli.ErrQuote,
+	},
+	{
+		in:  `foo\`,
+		err: cli.ErrQuote,
+	},
+}
+
+func TestSplit(t *testing.T) {
+	for _, tt := range splitTests {
+		args, err := cli.Split(tt.in)
+		if g, e := err, tt.err; g != e {
+			t.Errorf("Split(%q) error = %v, expected %v", tt.in, g, e)
+		}
+		if g, e := fmt.Sprintf("%q", args), fmt.Sprintf("%q", tt.args); g != e {
+			t.Errorf("Split(%q) = %v, expected %v", tt.in, g, e)
+		}
+	}
+}
+
+func TestShell(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	app := cli.NewCLI()
+	app.Name = "app"
+	app.Stdout = &stdout
+	app.Stderr = &stderr
+	app.Flags.Bool("v", false, "")
+	app.Add(cli.NewShellCommand())
+	flags := cli.NewFlagSet()
+	flags.Int("n", 0, "")
+	app.Add(&cli.Command{
+		Name:  []string{"echo"},
+		Flags: flags,
+		Action: func(ctx *cli.Context) error {
+			ctx.UI.Printf("%v %v %q\n", ctx.Bool("v"), ctx.Int("n"), ctx.Args)
+			return nil
+		},
+	})
+	app.Add(&cli.Command{
+		Name:  []string{"fail"},
+		Flags: cli.NewFlagSet(),
+		Action: func(ctx *cli.Context) error {
+			return errors.New("failed")
+		},
+	})
+	app.Stdin = strings.NewReader(strings.Join([]string{
+		`echo foo 'bar baz'`,
+		``,
+		`echo -n 1 foo`,
+		`ec foo`,
+		`fail`,
+		`echo -x`,
+		`'foo`,
+		`-v echo`,
+		`echo`,
+		`exit`,
+		`echo unreachable`,
+	}, "\n"))
+	if err := app.Run([]string{"shell"}); err != nil {
+		t.Fatal(err)
+	}
+	out := "app> false 0 [\"foo\" \"bar baz\"]\n" +
+		"app> " +
+		"app> false 1 [\"foo\"]\n" +
+		"app> false 0 [\"foo\"]\n" +
+		"app> " +
+		"app> usage: app echo\n\noptions:\n\n  -n <n>\n\n" +
+		"app> " +
+		"app> true 0 []\n" +
+		"app> false 0 []\n" +
+		"app> "
+	if err := testOut(stdout.String(), out); err != nil {
+		t.Error(err)
+	}
+	for _, s := range []string{
+		"app fail: failed\n",
+		"app echo: flag provided but not defined: -x\n",
+		"app shell: cli: unterminated quoted string\n",
+	} {
+		if !strings.Contains(stderr.String(), s) {
+			t.Errorf("expected %q in %q", s, stderr.String())
+		}
+	}
+
+	// global flags
+	stdout.Reset()
+	app.Stdin = strings.NewReader("echo\n")
+	if err := app.Run([]string{"-v", "shell"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(stdout.String(), "app> true 0 []\napp> \n"); err != nil {
+		t.Error(err)
+	}
+
+	if err := app.Run([]string{"shell", "arg"}); err != cli.ErrArgs {
+		t.Errorf("expected ErrArgs, got %#v", err)
+	}
+
+	// prompt
+	stdout.Reset()
+	app.Cmds[0].Action = (&cli.Shell{Prompt: "$ "}).Run
+	app.Stdin = strings.NewReader("")
+	if err := app.Run([]string{"shell"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(stdout.String(), "$ \n"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShellInterrupt(t *testing.T) {
+	app := cli.NewCLI()
+	app.Stdin = strings.NewReader("interrupt\ninterrupt\n")
+	app.Stdout = io.Discard
+	app.Stderr = io.Discard
+	app.Add(cli.NewShellCommand())
+	app.Add(&cli.Command{
+		Name:  []string{"interrupt"},
+		Flags: cli.NewFlagSet(),
+		Action: func(ctx *cli.Context) error {
+			ctx.Interrupt()
+			return nil
+		},
+	})
+	if err := app.Run([]string{"shell"}); err != (cli.Interrupt{}) {
+		t.Errorf("expected Interrupt, got %#v", err)
+	}
+}
+
+var completeTests = []struct {
+	line string
+	list []string
+}{
+	{
+		line: "",
+		list: []string{"shell ", "echo ", "fail ", "help "},
+	},
+	{
+		line: "echo -",
+		list: []string{"echo -v ", "echo -n "},
+	},
+	{
+		line: "-v e",
+		list: []string{"-v shell ", "-v echo ", "-v fail ", "-v help "},
+	},
+	{
+		line: "help ",
+		list: []string{"help shell ", "help echo ", "help fail ", "help help ", "help topic "},
+	},
+	{
+		line: "help echo ",
+	},
+	{
+		line: "foo ",
+	},
+}
+
+func TestCompleteCommand(t *testing.T) {
+	app := cli.NewCLI()
+	app.Flags.Bool("v", false, "")
+	app.Add(cli.NewShellCommand())
+	flags := cli.NewFlagSet()
+	flags.Int("n", 0, "")
+	app.Add(&cli.Command{
+		Name:  []string{"echo"},
+		Flags: flags,
+	})
+	app.Add(&cli.Command{
+		Name:  []string{"fail"},
+		Flags: cli.NewFlagSet(),
+	})
+	app.Add(&cli.Command{
+		Name:   []string{"hidden"},
+		Flags:  cli.NewFlagSet(),
+		Hidden: true,
+	})
+	app.Add(cli.NewHelpCommand())
+	app.AddTopic(&cli.Topic{Name: "topic"})
+	for _, tt := range completeTests {
+		list := cli.CompleteCommand(app, tt.line)
+		if g, e := fmt.Sprintf("%q", list), fmt.Sprintf("%q", tt.list); g != e {
+			t.Errorf("completeCommand(%q) = %v, expected %v", tt.line, g, e)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+//
+// go.cli :: signal.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+func (ui *CLI) notify() (stop func()) {
+	ch := make(chan os.Signal, 2)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		n := 0
+		for {
+			select {
+			case <-ch:
+				n++
+				if n == 1 {
+					ui.Interrupt()
+				} else {
+					// force exit
+					exit := ui.Exit
+					if exit == nil {
+						exit = os.Exit
+					}
+					exit(130)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,64 @@
+//
+// go.cli :: signal_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hattya/go.cli"
+)
+
+func TestHandleSignals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sending signals is not supported")
+	}
+
+	code := make(chan int, 1)
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := cli.NewCLI()
+	app.HandleSignals = true
+	app.Stdout = io.Discard
+	app.Stderr = io.Discard
+	app.Exit = func(i int) { code <- i }
+	app.Action = func(ctx *cli.Context) error {
+		if err := p.Signal(os.Interrupt); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Context().Done():
+		case <-time.After(10 * time.Second):
+			t.Error("not interrupted")
+		}
+
+		if err := p.Signal(os.Interrupt); err != nil {
+			return err
+		}
+		select {
+		case g := <-code:
+			if e := 130; g != e {
+				t.Errorf("exit code = %v, expected %v", g, e)
+			}
+		case <-time.After(10 * time.Second):
+			t.Error("not exited")
+		}
+		return nil
+	}
+	switch err := app.Run(nil).(type) {
+	case cli.Interrupt:
+	default:
+		t.Errorf("expected Interrupt, got %#v", err)
+	}
+}
@@ -0,0 +1,88 @@
+//
+// go.cli :: topic.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Topic struct {
+	Name string
+	Desc string
+	Text string
+}
+
+func (ui *CLI) AddTopic(t *Topic) {
+	ui.Topics = append(ui.Topics, t)
+}
+
+func FindTopic(topics []*Topic, name string) (*Topic, error) {
+	var list []*Topic
+	for _, t := range topics {
+		switch {
+		case t.Name == name:
+			return t, nil
+		case name != "" && strings.HasPrefix(t.Name, name):
+			list = append(list, t)
+		}
+	}
+
+	switch len(list) {
+	case 0:
+		return nil, TopicError{Name: name}
+	case 1:
+		return list[0], nil
+	}
+	err := TopicError{Name: name}
+	for _, t := range list {
+		err.List = append(err.List, t.Name)
+	}
+	sort.Strings(err.List)
+	return nil, err
+}
+
+type TopicError struct {
+	Name string
+	List []string
+}
+
+func (e TopicError) Error() string {
+	if len(e.List) == 0 {
+		return fmt.Sprintf("unknown help topic '%v'", e.Name)
+	}
+	return fmt.Sprintf("help topic '%v' is ambiguous (%v)", e.Name, strings.Join(e.List, ", "))
+}
+
+func ShowTopic(ctx *Context, t *Topic) error {
+	s := strings.TrimRight(Dedent(t.Text), "\n")
+	if s == "" {
+		return nil
+	}
+	_, err := ctx.UI.Println(s)
+	return err
+}
+
+func topics(topics []*Topic) []*Topic {
+	list := make([]*Topic, len(topics))
+	copy(list, topics)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func formatTopic(t *Topic, sep string, th *Theme) string {
+	var b strings.Builder
+	b.WriteString(th.Command.Render(t.Name))
+	if t.Desc != "" {
+		b.WriteString(sep)
+		b.WriteString(strings.TrimSpace(strings.Split(t.Desc, "\n")[0]))
+	}
+	return b.String()
+}
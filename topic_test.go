@@ -0,0 +1,158 @@
+//
+// go.cli :: topic_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hattya/go.cli"
+)
+
+var topicTests = []struct {
+	args []string
+	err  bool
+	out  string
+}{
+	{
+		args: []string{"help", "config"},
+		out: cli.Dedent(`
+			configuration
+
+			    [section]
+			    key = value
+		`),
+	},
+	{
+		args: []string{"help", "rev"},
+		out: cli.Dedent(`
+			revisions
+		`),
+	},
+	{
+		args: []string{"help", "co"},
+		err:  true,
+		out: cli.Dedent(`
+			%v: help topic 'co' is ambiguous (config, convert)
+			type '%[1]v help' for usage
+		`),
+	},
+	{
+		args: []string{"help", "cmd"},
+		out: cli.Dedent(`
+			usage: %v cmd
+		`),
+	},
+	{
+		args: []string{"help", "_"},
+		err:  true,
+		out: cli.Dedent(`
+			%v: unknown command '_'
+			type '%[1]v help' for usage
+		`),
+	},
+	{
+		args: []string{"help", "config", "_"},
+		err:  true,
+		out: cli.Dedent(`
+			%v: unknown command 'config'
+			type '%[1]v help' for usage
+		`),
+	},
+}
+
+func TestTopic(t *testing.T) {
+	for _, tt := range topicTests {
+		var b bytes.Buffer
+		app := cli.NewCLI()
+		app.Stdout = &b
+		app.Stderr = &b
+		app.Add(cli.NewHelpCommand())
+		app.Add(&cli.Command{
+			Name: []string{"cmd"},
+		})
+		app.AddTopic(&cli.Topic{
+			Name: "config",
+			Desc: "configuration file",
+			Text: `
+				configuration
+
+				    [section]
+				    key = value
+			`,
+		})
+		app.AddTopic(&cli.Topic{
+			Name: "convert",
+		})
+		app.AddTopic(&cli.Topic{
+			Name: "revisions",
+			Text: "revisions",
+		})
+		switch err := app.Run(tt.args); {
+		case tt.err && err == nil:
+			t.Fatal("expected error")
+		case !tt.err && err != nil:
+			t.Fatal(err)
+		}
+		out := tt.out
+		if strings.Contains(out, "%v") {
+			out = fmt.Sprintf(out, app.Name)
+		}
+		if err := testOut(b.String(), out); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestTopicHelp(t *testing.T) {
+	var b bytes.Buffer
+	app := cli.NewCLI()
+	app.Stdout = &b
+	app.Add(cli.NewHelpCommand())
+	app.AddTopic(&cli.Topic{
+		Name: "revisions",
+		Desc: "specifying revisions",
+	})
+	app.AddTopic(&cli.Topic{
+		Name: "config",
+		Desc: "configuration file\nsecond line",
+	})
+	if err := app.Run([]string{"help"}); err != nil {
+		t.Fatal(err)
+	}
+	out := cli.Dedent(`
+		usage: %[1]v
+
+		commands:
+
+		  help    show help for a specified command
+
+		additional help topics:
+
+		  config       configuration file
+		  revisions    specifying revisions
+
+		%[2]v
+
+	`)
+	if err := testOut(b.String(), fmt.Sprintf(out, app.Name, options)); err != nil {
+		t.Error(err)
+	}
+
+	// command help does not list topics
+	b.Reset()
+	if err := app.Run([]string{"help", "help"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testOut(b.String(), fmt.Sprintf("usage: %v help [<command>]\n\nshow help for a specified command\n\n", app.Name)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,225 @@
+//
+// go.cli :: width.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+const DefaultWidth = 80
+
+func Width(w io.Writer) int {
+	return width(w, os.Getenv)
+}
+
+func width(w io.Writer, getenv func(string) string) int {
+	if p, ok := w.(*pager); ok {
+		w = p.out
+	}
+	if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		if n, _, err := term.GetSize(int(f.Fd())); err == nil && n > 0 {
+			return n
+		}
+	}
+	if n, err := strconv.Atoi(getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return DefaultWidth
+}
+
+func StringWidth(s string) int {
+	n := 0
+	for len(s) > 0 {
+		if i := skipEscape(s); i > 0 {
+			s = s[i:]
+			continue
+		}
+		r, w := utf8.DecodeRuneInString(s)
+		n += RuneWidth(r)
+		s = s[w:]
+	}
+	return n
+}
+
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || (0x7f <= r && r < 0xa0):
+		return 0
+	case r < 0x1100:
+		if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+			return 0
+		}
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+	i := sort.Search(len(wideTable), func(i int) bool { return r <= wideTable[i][1] })
+	if i < len(wideTable) && wideTable[i][0] <= r {
+		return 2
+	}
+	return 1
+}
+
+// East Asian Wide (W) and Fullwidth (F)
+var wideTable = [][2]rune{
+	{0x1100, 0x115f},
+	{0x231a, 0x231b},
+	{0x2329, 0x232a},
+	{0x23e9, 0x23ec},
+	{0x23f0, 0x23f0},
+	{0x23f3, 0x23f3},
+	{0x25fd, 0x25fe},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x267f, 0x267f},
+	{0x2693, 0x2693},
+	{0x26a1, 0x26a1},
+	{0x26aa, 0x26ab},
+	{0x26bd, 0x26be},
+	{0x26c4, 0x26c5},
+	{0x26ce, 0x26ce},
+	{0x26d4, 0x26d4},
+	{0x26ea, 0x26ea},
+	{0x26f2, 0x26f3},
+	{0x26f5, 0x26f5},
+	{0x26fa, 0x26fa},
+	{0x26fd, 0x26fd},
+	{0x2705, 0x2705},
+	{0x270a, 0x270b},
+	{0x2728, 0x2728},
+	{0x274c, 0x274c},
+	{0x274e, 0x274e},
+	{0x2753, 0x2755},
+	{0x2757, 0x2757},
+	{0x2795, 0x2797},
+	{0x27b0, 0x27b0},
+	{0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c},
+	{0x2b50, 0x2b50},
+	{0x2b55, 0x2b55},
+	{0x2e80, 0x303e},
+	{0x3041, 0x3247},
+	{0x3250, 0x4dbf},
+	{0x4e00, 0xa4c6},
+	{0xa960, 0xa97c},
+	{0xac00, 0xd7a3},
+	{0xf900, 0xfaff},
+	{0xfe10, 0xfe19},
+	{0xfe30, 0xfe6b},
+	{0xff01, 0xff60},
+	{0xffe0, 0xffe6},
+	{0x16fe0, 0x16fe4},
+	{0x16ff0, 0x16ff1},
+	{0x17000, 0x18cd5},
+	{0x18d00, 0x18d08},
+	{0x1aff0, 0x1b2fb},
+	{0x1f004, 0x1f004},
+	{0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e},
+	{0x1f191, 0x1f19a},
+	{0x1f200, 0x1f202},
+	{0x1f210, 0x1f23b},
+	{0x1f240, 0x1f248},
+	{0x1f250, 0x1f251},
+	{0x1f260, 0x1f265},
+	{0x1f300, 0x1f320},
+	{0x1f32d, 0x1f335},
+	{0x1f337, 0x1f37c},
+	{0x1f37e, 0x1f393},
+	{0x1f3a0, 0x1f3ca},
+	{0x1f3cf, 0x1f3d3},
+	{0x1f3e0, 0x1f3f0},
+	{0x1f3f4, 0x1f3f4},
+	{0x1f3f8, 0x1f43e},
+	{0x1f440, 0x1f440},
+	{0x1f442, 0x1f4fc},
+	{0x1f4ff, 0x1f53d},
+	{0x1f54b, 0x1f54e},
+	{0x1f550, 0x1f567},
+	{0x1f57a, 0x1f57a},
+	{0x1f595, 0x1f596},
+	{0x1f5a4, 0x1f5a4},
+	{0x1f5fb, 0x1f64f},
+	{0x1f680, 0x1f6c5},
+	{0x1f6cc, 0x1f6cc},
+	{0x1f6d0, 0x1f6d2},
+	{0x1f6d5, 0x1f6d7},
+	{0x1f6dc, 0x1f6df},
+	{0x1f6eb, 0x1f6ec},
+	{0x1f6f4, 0x1f6fc},
+	{0x1f7e0, 0x1f7eb},
+	{0x1f7f0, 0x1f7f0},
+	{0x1f90c, 0x1f93a},
+	{0x1f93c, 0x1f945},
+	{0x1f947, 0x1f9ff},
+	{0x1fa70, 0x1fa7c},
+	{0x1fa80, 0x1fa89},
+	{0x1fa8f, 0x1fac6},
+	{0x1face, 0x1fadc},
+	{0x1fadf, 0x1fae9},
+	{0x1faf0, 0x1faf8},
+	{0x20000, 0x2fffd},
+	{0x30000, 0x3fffd},
+}
+
+func Wrap(s string, width int) []string {
+	if width <= 0 || StringWidth(s) <= width {
+		return []string{s}
+	}
+
+	var lines []string
+	var b strings.Builder
+	n := 0
+	space := false
+	for len(s) > 0 {
+		// next token is a run of narrow characters or a wide character
+		r, w := utf8.DecodeRuneInString(s)
+		if r == ' ' {
+			space = true
+			s = s[w:]
+			continue
+		}
+		tok := s[:w]
+		if RuneWidth(r) < 2 {
+			i := strings.IndexFunc(s, func(r rune) bool { return r == ' ' || RuneWidth(r) > 1 })
+			if i == -1 {
+				i = len(s)
+			}
+			tok = s[:i]
+		}
+		s = s[len(tok):]
+
+		tw := StringWidth(tok)
+		sep := 0
+		if space && n > 0 {
+			sep = 1
+		}
+		if n > 0 && width < n+sep+tw {
+			lines = append(lines, b.String())
+			b.Reset()
+			n = 0
+			sep = 0
+		}
+		if sep > 0 {
+			b.WriteRune(' ')
+		}
+		b.WriteString(tok)
+		n += sep + tw
+		space = false
+	}
+	return append(lines, b.String())
+}
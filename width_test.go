@@ -0,0 +1,112 @@
+//
+// go.cli :: width_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package cli_test
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/hattya/go.cli"
+)
+
+func TestWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+	if g, e := cli.Width(io.Discard), cli.DefaultWidth; g != e {
+		t.Errorf("Width() = %v, expected %v", g, e)
+	}
+	t.Setenv("COLUMNS", "40")
+	if g, e := cli.Width(io.Discard), 40; g != e {
+		t.Errorf("Width() = %v, expected %v", g, e)
+	}
+	t.Setenv("COLUMNS", "-1")
+	if g, e := cli.Width(io.Discard), cli.DefaultWidth; g != e {
+		t.Errorf("Width() = %v, expected %v", g, e)
+	}
+}
+
+var stringWidthTests = []struct {
+	in  string
+	out int
+}{
+	{"", 0},
+	{"abc", 3},
+	{"\t", 0},
+	{"é", 1},
+	{"é", 1},
+	{"日本語", 6},
+	{"ｱｲｳ", 3},
+	{"ＡＢＣ", 6},
+	{"한국어", 6},
+	{"😀", 2},
+	{"\U00020000", 2},
+}
+
+func TestStringWidth(t *testing.T) {
+	for _, tt := range stringWidthTests {
+		if g, e := cli.StringWidth(tt.in), tt.out; g != e {
+			t.Errorf("StringWidth(%q) = %v, expected %v", tt.in, g, e)
+		}
+	}
+}
+
+var wrapTests = []struct {
+	in    string
+	width int
+	out   []string
+}{
+	{
+		in:    "",
+		width: 10,
+		out:   []string{""},
+	},
+	{
+		in:    "foo bar baz",
+		width: 0,
+		out:   []string{"foo bar baz"},
+	},
+	{
+		in:    "foo bar baz",
+		width: 11,
+		out:   []string{"foo bar baz"},
+	},
+	{
+		in:    "foo bar baz",
+		width: 7,
+		out:   []string{"foo bar", "baz"},
+	},
+	{
+		in:    "foo  bar   baz",
+		width: 3,
+		out:   []string{"foo", "bar", "baz"},
+	},
+	{
+		in:    "foobarbaz qux",
+		width: 3,
+		out:   []string{"foobarbaz", "qux"},
+	},
+	{
+		in:    "日本語の文章",
+		width: 7,
+		out:   []string{"日本語", "の文章"},
+	},
+	{
+		in:    "go 言語",
+		width: 5,
+		out:   []string{"go 言", "語"},
+	},
+}
+
+func TestWrap(t *testing.T) {
+	for _, tt := range wrapTests {
+		if g, e := cli.Wrap(tt.in, tt.width), tt.out; !reflect.DeepEqual(g, e) {
+			t.Errorf("Wrap(%q, %v) = %q, expected %q", tt.in, tt.width, g, e)
+		}
+	}
+}